@@ -0,0 +1,176 @@
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+const defaultMaxSize = 4096
+
+var (
+	errNoSprites       = errors.New("no sprites to pack")
+	errNegativeOptions = errors.New("padding and extrude must not be negative")
+)
+
+// Sprite is a named image to be packed into an atlas
+type Sprite struct {
+	Name  string
+	Image image.Image
+}
+
+// Options controls the layout of a packed atlas
+type Options struct {
+	Padding int // empty pixels left between neighbouring sprites
+	Extrude int // pixels each sprite's edge is repeated outward to stop filtering bleed
+	MaxSize int // largest width or height the atlas may grow to, defaults to 4096
+}
+
+// Region is where a sprite ended up in the atlas. U and V are normalised, with
+// V measured from the top row of the image as it is uploaded by render.NewTexture.
+type Region struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+
+	U0 float32 `json:"u0"`
+	V0 float32 `json:"v0"`
+	U1 float32 `json:"u1"`
+	V1 float32 `json:"v1"`
+}
+
+type Atlas struct {
+	Image   *image.RGBA
+	Regions map[string]Region
+}
+
+// Pack lays the sprites out with MaxRects, growing the atlas in powers of two
+// until everything fits or MaxSize is reached
+func Pack(sprites []Sprite, opts Options) (*Atlas, error) {
+	if len(sprites) == 0 {
+		return nil, errNoSprites
+	}
+	if opts.Padding < 0 || opts.Extrude < 0 {
+		return nil, errNegativeOptions
+	}
+	seen := make(map[string]bool, len(sprites))
+	for _, s := range sprites {
+		if seen[s.Name] {
+			return nil, fmt.Errorf("duplicate sprite name %q", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = defaultMaxSize
+	}
+
+	order := make([]Sprite, len(sprites))
+	copy(order, sprites)
+	sort.SliceStable(order, func(i, j int) bool {
+		bi, bj := order[i].Image.Bounds(), order[j].Image.Bounds()
+		if bi.Dy() != bj.Dy() {
+			return bi.Dy() > bj.Dy()
+		}
+		if bi.Dx() != bj.Dx() {
+			return bi.Dx() > bj.Dx()
+		}
+		return order[i].Name < order[j].Name
+	})
+
+	border := opts.Extrude*2 + opts.Padding
+	area := 0
+	for _, s := range order {
+		b := s.Image.Bounds()
+		w, h := b.Dx()+border, b.Dy()+border
+		if w > opts.MaxSize || h > opts.MaxSize {
+			return nil, fmt.Errorf("sprite %s (%dx%d) is larger than the max atlas size %d", s.Name, b.Dx(), b.Dy(), opts.MaxSize)
+		}
+		area += w * h
+	}
+
+	width := nextPowerOfTwo(int(math.Sqrt(float64(area))))
+	height := width
+	for width <= opts.MaxSize && height <= opts.MaxSize {
+		if placed, ok := tryPack(order, width, height, border); ok {
+			return compose(order, placed, width, height, opts), nil
+		}
+		if width <= height {
+			width *= 2
+		} else {
+			height *= 2
+		}
+	}
+
+	return nil, fmt.Errorf("sprites do not fit in a %dx%d atlas", opts.MaxSize, opts.MaxSize)
+}
+
+func tryPack(sprites []Sprite, width, height, border int) ([]image.Rectangle, bool) {
+	bin := newMaxRects(width, height)
+	placed := make([]image.Rectangle, len(sprites))
+	for i, s := range sprites {
+		b := s.Image.Bounds()
+		r, ok := bin.insert(b.Dx()+border, b.Dy()+border)
+		if !ok {
+			return nil, false
+		}
+		placed[i] = r
+	}
+	return placed, true
+}
+
+func compose(sprites []Sprite, placed []image.Rectangle, width, height int, opts Options) *Atlas {
+	a := &Atlas{
+		Image:   image.NewRGBA(image.Rect(0, 0, width, height)),
+		Regions: make(map[string]Region, len(sprites)),
+	}
+
+	for i, s := range sprites {
+		b := s.Image.Bounds()
+		dst := image.Rect(0, 0, b.Dx(), b.Dy()).Add(placed[i].Min).Add(image.Pt(opts.Extrude, opts.Extrude))
+		draw.Draw(a.Image, dst, s.Image, b.Min, draw.Src)
+		extrude(a.Image, dst, opts.Extrude)
+
+		a.Regions[s.Name] = Region{
+			X:  dst.Min.X,
+			Y:  dst.Min.Y,
+			W:  dst.Dx(),
+			H:  dst.Dy(),
+			U0: float32(dst.Min.X) / float32(width),
+			V0: float32(dst.Min.Y) / float32(height),
+			U1: float32(dst.Max.X) / float32(width),
+			V1: float32(dst.Max.Y) / float32(height),
+		}
+	}
+	return a
+}
+
+// extrude repeats the outermost pixels of r outward by n pixels, corners included
+func extrude(img *image.RGBA, r image.Rectangle, n int) {
+	if n == 0 || r.Empty() {
+		return
+	}
+	for i := 1; i <= n; i++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, r.Min.Y-i, img.At(x, r.Min.Y))
+			img.Set(x, r.Max.Y-1+i, img.At(x, r.Max.Y-1))
+		}
+	}
+	for i := 1; i <= n; i++ {
+		for y := r.Min.Y - n; y < r.Max.Y+n; y++ {
+			img.Set(r.Min.X-i, y, img.At(r.Min.X, y))
+			img.Set(r.Max.X-1+i, y, img.At(r.Max.X-1, y))
+		}
+	}
+}
+
+func nextPowerOfTwo(v int) int {
+	p := 1
+	for p < v {
+		p *= 2
+	}
+	return p
+}
@@ -0,0 +1,129 @@
+package atlas
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"testing"
+)
+
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+	return img
+}
+
+func testSprites() []Sprite {
+	return []Sprite{
+		{Name: "red", Image: solid(30, 20, color.RGBA{R: 255, A: 255})},
+		{Name: "green", Image: solid(16, 16, color.RGBA{G: 255, A: 255})},
+		{Name: "blue", Image: solid(8, 40, color.RGBA{B: 255, A: 255})},
+		{Name: "white", Image: solid(5, 5, color.RGBA{R: 255, G: 255, B: 255, A: 255})},
+	}
+}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"tight", Options{}},
+		{"padding", Options{Padding: 2}},
+		{"extrude", Options{Extrude: 1}},
+		{"padding and extrude", Options{Padding: 3, Extrude: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sprites := testSprites()
+			a, err := Pack(sprites, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size := a.Image.Rect.Size()
+			if len(a.Regions) != len(sprites) {
+				t.Fatalf("got %d regions, want %d", len(a.Regions), len(sprites))
+			}
+
+			// each region with its border must stay inside the atlas and clear of the others
+			border := tt.opts.Extrude
+			var outer []image.Rectangle
+			for _, s := range sprites {
+				r := a.Regions[s.Name]
+				if r.W != s.Image.Bounds().Dx() || r.H != s.Image.Bounds().Dy() {
+					t.Errorf("%s: region is %dx%d, want the sprite size", s.Name, r.W, r.H)
+				}
+				o := image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H).Inset(-border)
+				if !o.In(a.Image.Rect) {
+					t.Errorf("%s: %v lies outside the %v atlas", s.Name, o, size)
+				}
+				for _, p := range outer {
+					if p.Overlaps(o) {
+						t.Errorf("%s: %v overlaps %v", s.Name, o, p)
+					}
+				}
+				outer = append(outer, o)
+
+				if r.U0 != float32(r.X)/float32(size.X) || r.V1 != float32(r.Y+r.H)/float32(size.Y) {
+					t.Errorf("%s: uv %v,%v does not match the pixel rectangle", s.Name, r.U0, r.V1)
+				}
+
+				// the sprite and its extruded border carry the sprite's colour
+				want := s.Image.At(0, 0)
+				for y := o.Min.Y; y < o.Max.Y; y++ {
+					for x := o.Min.X; x < o.Max.X; x++ {
+						if got := a.Image.At(x, y); got != want {
+							t.Fatalf("%s: pixel %d,%d is %v, want %v", s.Name, x, y, got, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPackErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sprites []Sprite
+		opts    Options
+	}{
+		{"no sprites", nil, Options{}},
+		{"negative padding", testSprites(), Options{Padding: -1}},
+		{"negative extrude", testSprites(), Options{Extrude: -1}},
+		{"duplicate name", append(testSprites(), Sprite{Name: "red", Image: solid(1, 1, color.RGBA{})}), Options{}},
+		{"sprite too large", testSprites(), Options{MaxSize: 32}},
+		{"does not fit", testSprites(), Options{MaxSize: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Pack(tt.sprites, tt.opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	a, err := Pack(testSprites(), Options{Padding: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := a.Manifest("sprites.png")
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadManifest(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("got %+v\nwant %+v", got, m)
+	}
+	if got.Width != a.Image.Rect.Dx() || got.Height != a.Image.Rect.Dy() {
+		t.Errorf("manifest size %dx%d does not match the atlas", got.Width, got.Height)
+	}
+}
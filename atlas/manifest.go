@@ -0,0 +1,35 @@
+package atlas
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Manifest is the JSON description written alongside an atlas image
+type Manifest struct {
+	Image   string            `json:"image"`
+	Width   int               `json:"width"`
+	Height  int               `json:"height"`
+	Regions map[string]Region `json:"regions"`
+}
+
+func (a *Atlas) Manifest(imageFile string) Manifest {
+	return Manifest{
+		Image:   imageFile,
+		Width:   a.Image.Rect.Dx(),
+		Height:  a.Image.Rect.Dy(),
+		Regions: a.Regions,
+	}
+}
+
+func (m Manifest) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+func ReadManifest(r io.Reader) (Manifest, error) {
+	var m Manifest
+	err := json.NewDecoder(r).Decode(&m)
+	return m, err
+}
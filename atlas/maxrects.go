@@ -0,0 +1,91 @@
+package atlas
+
+import "image"
+
+// maxRects is a MaxRects bin using the best short side fit heuristic
+type maxRects struct {
+	width  int
+	height int
+	free   []image.Rectangle
+}
+
+func newMaxRects(width, height int) *maxRects {
+	return &maxRects{
+		width:  width,
+		height: height,
+		free:   []image.Rectangle{image.Rect(0, 0, width, height)},
+	}
+}
+
+func (m *maxRects) insert(width, height int) (image.Rectangle, bool) {
+	best := image.Rectangle{}
+	bestShort, bestLong := -1, -1
+
+	for _, f := range m.free {
+		fw, fh := f.Dx(), f.Dy()
+		if width > fw || height > fh {
+			continue
+		}
+		short, long := fw-width, fh-height
+		if short > long {
+			short, long = long, short
+		}
+		if bestShort == -1 || short < bestShort || (short == bestShort && long < bestLong) {
+			best = image.Rect(f.Min.X, f.Min.Y, f.Min.X+width, f.Min.Y+height)
+			bestShort, bestLong = short, long
+		}
+	}
+
+	if bestShort == -1 {
+		return image.Rectangle{}, false
+	}
+
+	m.place(best)
+	return best, true
+}
+
+func (m *maxRects) place(used image.Rectangle) {
+	var free []image.Rectangle
+	for _, f := range m.free {
+		if !f.Overlaps(used) {
+			free = append(free, f)
+			continue
+		}
+		if used.Min.X > f.Min.X {
+			free = append(free, image.Rect(f.Min.X, f.Min.Y, used.Min.X, f.Max.Y))
+		}
+		if used.Max.X < f.Max.X {
+			free = append(free, image.Rect(used.Max.X, f.Min.Y, f.Max.X, f.Max.Y))
+		}
+		if used.Min.Y > f.Min.Y {
+			free = append(free, image.Rect(f.Min.X, f.Min.Y, f.Max.X, used.Min.Y))
+		}
+		if used.Max.Y < f.Max.Y {
+			free = append(free, image.Rect(f.Min.X, used.Max.Y, f.Max.X, f.Max.Y))
+		}
+	}
+	m.free = prune(free)
+}
+
+// prune drops every free rectangle that is wholly contained in another
+func prune(free []image.Rectangle) []image.Rectangle {
+	var kept []image.Rectangle
+	for i, a := range free {
+		contained := false
+		for j, b := range free {
+			if i == j || !a.In(b) {
+				continue
+			}
+			// identical rectangles: keep only the first
+			if a == b && i < j {
+				continue
+			}
+			contained = true
+			break
+		}
+		if !contained {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevholditch/opengl-playground/atlas"
+)
+
+func main() {
+	in := flag.String("in", ".", "directory of png files to pack")
+	out := flag.String("out", "atlas.png", "atlas image to write")
+	manifest := flag.String("manifest", "atlas.json", "json manifest to write")
+	padding := flag.Int("padding", 2, "pixels between sprites")
+	extrude := flag.Int("extrude", 1, "pixels to extrude sprite edges by")
+	maxSize := flag.Int("max", 4096, "maximum atlas width and height")
+	flag.Parse()
+
+	sprites, err := loadSprites(*in)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	a, err := atlas.Pack(sprites, atlas.Options{Padding: *padding, Extrude: *extrude, MaxSize: *maxSize})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := writePNG(*out, a.Image); err != nil {
+		log.Fatalln(err)
+	}
+
+	// the manifest refers to the image relative to itself so the pair can be moved together
+	rel, err := filepath.Rel(filepath.Dir(*manifest), *out)
+	if err != nil {
+		rel = *out
+	}
+	if err := writeManifest(*manifest, a.Manifest(filepath.ToSlash(rel))); err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Printf("packed %d sprites into %dx%d atlas %s\n", len(sprites), a.Image.Rect.Dx(), a.Image.Rect.Dy(), *out)
+}
+
+func loadSprites(dir string) ([]atlas.Sprite, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sprites []atlas.Sprite
+	for _, f := range files {
+		if f.IsDir() || strings.ToLower(filepath.Ext(f.Name())) != ".png" {
+			continue
+		}
+		img, err := readPNG(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sprites = append(sprites, atlas.Sprite{
+			Name:  strings.TrimSuffix(f.Name(), filepath.Ext(f.Name())),
+			Image: img,
+		})
+	}
+	return sprites, nil
+}
+
+func readPNG(file string) (image.Image, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(file string, img image.Image) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+func writeManifest(file string, m atlas.Manifest) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Write(f)
+}
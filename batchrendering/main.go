@@ -3,7 +3,6 @@ package main
 import (
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/go-gl/mathgl/mgl32"
 	"github.com/kevholditch/opengl-playground/render"
 	"runtime"
 )
@@ -29,6 +28,7 @@ func main() {
 		Height:       height,
 		Title:        "Batch Rendering Demo - Kevin Holditch",
 		SwapInterval: 1,
+		Resizable:    true,
 	})
 
 	if err != nil {
@@ -59,7 +59,8 @@ func main() {
 	ib := render.NewIndexBuffer(indices)
 
 
-	proj := mgl32.Ortho(0, width, 0, height, -1.0, 1.0)
+	camera := render.NewOrthoCamera(w)
+	proj := camera.Projection()
 
 	va.AddBuffer(render.NewVertexBuffer(buffer), render.NewVertexBufferLayout().AddLayoutFloats(2).AddLayoutFloats(4))
 
@@ -91,7 +92,7 @@ func main() {
 		render.CheckErrors()
 
 		program.Bind()
-		program.SetUniformMat4f("u_MVP", proj)
+		program.SetUniformMat4f("u_MVP", camera.Projection())
 
 		render.Render(va, ib, program)
 
@@ -0,0 +1,110 @@
+package bmfont
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var binaryMagic = []byte("BMF")
+
+const binaryVersion = 3
+
+const (
+	blockInfo    = 1
+	blockCommon  = 2
+	blockPages   = 3
+	blockChars   = 4
+	blockKerning = 5
+)
+
+const (
+	charRecordSize    = 20
+	kerningRecordSize = 10
+)
+
+var errTruncatedBlock = errors.New("bmfont: truncated block")
+
+func parseBinary(data []byte) (*Font, error) {
+	if len(data) < 4 {
+		return nil, errTruncatedBlock
+	}
+	if data[3] != binaryVersion {
+		return nil, fmt.Errorf("bmfont: unsupported binary version %d", data[3])
+	}
+	data = data[4:]
+
+	f := newFont()
+	le := binary.LittleEndian
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, errTruncatedBlock
+		}
+		kind, size := data[0], int(le.Uint32(data[1:5]))
+		data = data[5:]
+		if size > len(data) {
+			return nil, errTruncatedBlock
+		}
+		block := data[:size]
+		data = data[size:]
+
+		switch kind {
+		case blockInfo:
+			if len(block) < 14 {
+				return nil, errTruncatedBlock
+			}
+			f.Size = int(int16(le.Uint16(block[0:2])))
+			if f.Size < 0 {
+				f.Size = -f.Size
+			}
+			f.Face = cString(block[14:])
+		case blockCommon:
+			if len(block) < 10 {
+				return nil, errTruncatedBlock
+			}
+			f.LineHeight = int(le.Uint16(block[0:2]))
+			f.Base = int(le.Uint16(block[2:4]))
+			f.ScaleW = int(le.Uint16(block[4:6]))
+			f.ScaleH = int(le.Uint16(block[6:8]))
+		case blockPages:
+			for len(block) > 0 {
+				name := cString(block)
+				f.Pages = append(f.Pages, name)
+				if len(name) >= len(block) {
+					break
+				}
+				block = block[len(name)+1:]
+			}
+		case blockChars:
+			for ; len(block) >= charRecordSize; block = block[charRecordSize:] {
+				c := Char{
+					ID:       rune(le.Uint32(block[0:4])),
+					X:        int(le.Uint16(block[4:6])),
+					Y:        int(le.Uint16(block[6:8])),
+					Width:    int(le.Uint16(block[8:10])),
+					Height:   int(le.Uint16(block[10:12])),
+					XOffset:  int(int16(le.Uint16(block[12:14]))),
+					YOffset:  int(int16(le.Uint16(block[14:16]))),
+					XAdvance: int(int16(le.Uint16(block[16:18]))),
+					Page:     int(block[18]),
+					Channel:  int(block[19]),
+				}
+				f.Chars[c.ID] = c
+			}
+		case blockKerning:
+			for ; len(block) >= kerningRecordSize; block = block[kerningRecordSize:] {
+				pair := KerningPair{First: rune(le.Uint32(block[0:4])), Second: rune(le.Uint32(block[4:8]))}
+				f.Kerning[pair] = int(int16(le.Uint16(block[8:10])))
+			}
+		}
+	}
+	return f, nil
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return string(b[:i])
+	}
+	return string(b)
+}
@@ -0,0 +1,230 @@
+package bmfont
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testFontText = `info face="Test Font" size=32 bold=0
+common lineHeight=40 base=30 scaleW=256 scaleH=128 pages=1 packed=0
+page id=0 file="test 0.png"
+chars count=4
+char id=65 x=10 y=20 width=20 height=24 xoffset=1 yoffset=6 xadvance=22 page=0 chnl=15
+char id=86 x=40 y=20 width=18 height=24 xoffset=0 yoffset=6 xadvance=20 page=0 chnl=15
+char id=32 x=0 y=0 width=0 height=0 xoffset=0 yoffset=0 xadvance=10 page=0 chnl=15
+char id=63 x=70 y=20 width=12 height=24 xoffset=2 yoffset=6 xadvance=16 page=0 chnl=15
+kernings count=1
+kerning first=65 second=86 amount=-3
+`
+
+func testFont() *Font {
+	return &Font{
+		Face:       "Test Font",
+		Size:       32,
+		LineHeight: 40,
+		Base:       30,
+		ScaleW:     256,
+		ScaleH:     128,
+		Pages:      []string{"test 0.png"},
+		Chars: map[rune]Char{
+			'A': {ID: 'A', X: 10, Y: 20, Width: 20, Height: 24, XOffset: 1, YOffset: 6, XAdvance: 22, Channel: 15},
+			'V': {ID: 'V', X: 40, Y: 20, Width: 18, Height: 24, YOffset: 6, XAdvance: 20, Channel: 15},
+			' ': {ID: ' ', XAdvance: 10, Channel: 15},
+			'?': {ID: '?', X: 70, Y: 20, Width: 12, Height: 24, XOffset: 2, YOffset: 6, XAdvance: 16, Channel: 15},
+		},
+		Kerning: map[KerningPair]int{{First: 'A', Second: 'V'}: -3},
+	}
+}
+
+// binaryFont encodes f in the version 3 binary format
+func binaryFont(f *Font) []byte {
+	le := binary.LittleEndian
+	var out bytes.Buffer
+	out.WriteString("BMF\x03")
+	block := func(kind byte, data []byte) {
+		out.WriteByte(kind)
+		binary.Write(&out, le, uint32(len(data)))
+		out.Write(data)
+	}
+
+	info := make([]byte, 14)
+	le.PutUint16(info, uint16(f.Size))
+	block(blockInfo, append(info, f.Face+"\x00"...))
+
+	common := make([]byte, 15)
+	le.PutUint16(common[0:], uint16(f.LineHeight))
+	le.PutUint16(common[2:], uint16(f.Base))
+	le.PutUint16(common[4:], uint16(f.ScaleW))
+	le.PutUint16(common[6:], uint16(f.ScaleH))
+	le.PutUint16(common[8:], uint16(len(f.Pages)))
+	block(blockCommon, common)
+
+	var pages []byte
+	for _, p := range f.Pages {
+		pages = append(pages, p+"\x00"...)
+	}
+	block(blockPages, pages)
+
+	var chars bytes.Buffer
+	for _, c := range f.Chars {
+		binary.Write(&chars, le, struct {
+			ID                   uint32
+			X, Y, Width, Height  uint16
+			XOffset, YOffset, XA int16
+			Page, Channel        uint8
+		}{uint32(c.ID), uint16(c.X), uint16(c.Y), uint16(c.Width), uint16(c.Height),
+			int16(c.XOffset), int16(c.YOffset), int16(c.XAdvance), uint8(c.Page), uint8(c.Channel)})
+	}
+	block(blockChars, chars.Bytes())
+
+	var kerning bytes.Buffer
+	for pair, amount := range f.Kerning {
+		binary.Write(&kerning, le, struct {
+			First, Second uint32
+			Amount        int16
+		}{uint32(pair.First), uint32(pair.Second), int16(amount)})
+	}
+	block(blockKerning, kerning.Bytes())
+	return out.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"text", []byte(testFontText)},
+		{"binary", binaryFont(testFont())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := testFont(); !reflect.DeepEqual(f, want) {
+				t.Errorf("got %+v\nwant %+v", f, want)
+			}
+			if k := f.Kern('A', 'V'); k != -3 {
+				t.Errorf("Kern(A, V) = %d, want -3", k)
+			}
+			if k := f.Kern('V', 'A'); k != 0 {
+				t.Errorf("Kern(V, A) = %d, want 0", k)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"bad number", "common lineHeight=abc"},
+		{"negative page id", "page id=-1 file=\"a.png\""},
+		{"huge page id", "common pages=2\npage id=2000000000 file=\"a.png\""},
+		{"page id past the count", "common pages=2\npage id=3 file=\"a.png\""},
+		{"huge page count", "common pages=2000000000"},
+		{"binary version", "BMF\x02"},
+		{"truncated block", "BMF\x03\x01\xff\x00\x00\x00"},
+		{"short block header", "BMF\x03\x01\x02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"declared count", "common pages=2\npage id=1 file=\"b.png\"\npage id=0 file=\"a.png\"", []string{"a.png", "b.png"}},
+		{"no count", "page id=0 file=\"a.png\"\npage id=1 file=\"b.png\"", []string{"a.png", "b.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(strings.NewReader(tt.text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(f.Pages, tt.want) {
+				t.Errorf("pages %q, want %q", f.Pages, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout(t *testing.T) {
+	a := func(x, y float32) Quad {
+		return Quad{Char: 'A', X0: x, Y0: y, X1: x + 20, Y1: y + 24,
+			U0: 10.0 / 256, V0: 20.0 / 128, U1: 30.0 / 256, V1: 44.0 / 128}
+	}
+	v := func(x, y float32) Quad {
+		return Quad{Char: 'V', X0: x, Y0: y, X1: x + 18, Y1: y + 24,
+			U0: 40.0 / 256, V0: 20.0 / 128, U1: 58.0 / 256, V1: 44.0 / 128}
+	}
+	fallback := func(x, y float32) Quad {
+		return Quad{Char: '?', X0: x, Y0: y, X1: x + 12, Y1: y + 24,
+			U0: 70.0 / 256, V0: 20.0 / 128, U1: 82.0 / 256, V1: 44.0 / 128}
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []Quad
+	}{
+		{"single glyph", "A", []Quad{a(101, 200)}},
+		// V is pulled 3px left by the A-V kerning pair
+		{"kerning", "AV", []Quad{a(101, 200), v(119, 200)}},
+		{"no kerning the other way", "VA", []Quad{v(100, 200), a(121, 200)}},
+		{"space advances without a quad", "A A", []Quad{a(101, 200), a(133, 200)}},
+		{"newline moves down a line", "A\nV", []Quad{a(101, 200), v(100, 160)}},
+		{"missing glyph falls back", "Z", []Quad{fallback(102, 200)}},
+		{"empty", "", nil},
+	}
+	f := testFont()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.Layout(tt.text, 100, 200, 1)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutScale(t *testing.T) {
+	q := testFont().Layout("A", 0, 0, 2)
+	want := Quad{Char: 'A', X0: 2, Y0: 0, X1: 42, Y1: 48,
+		U0: 10.0 / 256, V0: 20.0 / 128, U1: 30.0 / 256, V1: 44.0 / 128}
+	if len(q) != 1 || q[0] != want {
+		t.Errorf("got %+v, want %+v", q, want)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		text          string
+		width, height float32
+	}{
+		{"AV", 39, 40},
+		{"A\nAV", 39, 80},
+		{"A A", 54, 40},
+	}
+	f := testFont()
+	for _, tt := range tests {
+		w, h := f.Measure(tt.text, 1)
+		if w != tt.width || h != tt.height {
+			t.Errorf("Measure(%q) = %v, %v, want %v, %v", tt.text, w, h, tt.width, tt.height)
+		}
+	}
+}
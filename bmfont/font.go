@@ -0,0 +1,64 @@
+package bmfont
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+)
+
+// Font holds the glyph metrics of an AngelCode BMFont file. Page textures are
+// referenced by file name and are not loaded here.
+type Font struct {
+	Face       string
+	Size       int
+	LineHeight int
+	Base       int
+	ScaleW     int
+	ScaleH     int
+	Pages      []string
+	Chars      map[rune]Char
+	Kerning    map[KerningPair]int
+}
+
+// Char is the placement of a single glyph on a page, in pixels
+type Char struct {
+	ID       rune
+	X        int
+	Y        int
+	Width    int
+	Height   int
+	XOffset  int
+	YOffset  int
+	XAdvance int
+	Page     int
+	Channel  int
+}
+
+type KerningPair struct {
+	First  rune
+	Second rune
+}
+
+func newFont() *Font {
+	return &Font{
+		Chars:   map[rune]Char{},
+		Kerning: map[KerningPair]int{},
+	}
+}
+
+// Parse reads a font in either the text or binary .fnt format
+func Parse(r io.Reader) (*Font, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, binaryMagic) {
+		return parseBinary(data)
+	}
+	return parseText(bufio.NewScanner(bytes.NewReader(data)))
+}
+
+func (f *Font) Kern(first, second rune) int {
+	return f.Kerning[KerningPair{First: first, Second: second}]
+}
@@ -0,0 +1,106 @@
+package bmfont
+
+// Quad is one glyph positioned on screen. Positions are in a y-up space with
+// Y0 at the bottom edge; texture coordinates are normalised with V0 at the top
+// row of the page image.
+type Quad struct {
+	Char rune
+	Page int
+
+	X0 float32
+	Y0 float32
+	X1 float32
+	Y1 float32
+
+	U0 float32
+	V0 float32
+	U1 float32
+	V1 float32
+}
+
+const fallbackChar = '?'
+
+// Layout positions each glyph of text with the baseline of the first line at
+// (x, y). Newlines move down by the font's line height, kerning is applied
+// between neighbouring glyphs and characters the font lacks are drawn as '?'.
+func (f *Font) Layout(text string, x, y, scale float32) []Quad {
+	var quads []Quad
+
+	penX, penY := x, y
+	var prev rune = -1
+	for _, r := range text {
+		if r == '\n' {
+			penX = x
+			penY -= float32(f.LineHeight) * scale
+			prev = -1
+			continue
+		}
+
+		c, ok := f.glyph(r)
+		if !ok {
+			prev = -1
+			continue
+		}
+		if prev >= 0 {
+			penX += float32(f.Kern(prev, c.ID)) * scale
+		}
+
+		if c.Width > 0 && c.Height > 0 {
+			left := penX + float32(c.XOffset)*scale
+			top := penY + float32(f.Base-c.YOffset)*scale
+			quads = append(quads, Quad{
+				Char: c.ID,
+				Page: c.Page,
+				X0:   left,
+				Y0:   top - float32(c.Height)*scale,
+				X1:   left + float32(c.Width)*scale,
+				Y1:   top,
+				U0:   float32(c.X) / float32(f.ScaleW),
+				V0:   float32(c.Y) / float32(f.ScaleH),
+				U1:   float32(c.X+c.Width) / float32(f.ScaleW),
+				V1:   float32(c.Y+c.Height) / float32(f.ScaleH),
+			})
+		}
+
+		penX += float32(c.XAdvance) * scale
+		prev = c.ID
+	}
+	return quads
+}
+
+// Measure returns the width of the widest line and the total height of text
+func (f *Font) Measure(text string, scale float32) (float32, float32) {
+	var width, lineWidth float32
+	lines := 1
+	var prev rune = -1
+	for _, r := range text {
+		if r == '\n' {
+			lines++
+			lineWidth = 0
+			prev = -1
+			continue
+		}
+		c, ok := f.glyph(r)
+		if !ok {
+			prev = -1
+			continue
+		}
+		if prev >= 0 {
+			lineWidth += float32(f.Kern(prev, c.ID)) * scale
+		}
+		lineWidth += float32(c.XAdvance) * scale
+		if lineWidth > width {
+			width = lineWidth
+		}
+		prev = c.ID
+	}
+	return width, float32(lines*f.LineHeight) * scale
+}
+
+func (f *Font) glyph(r rune) (Char, bool) {
+	if c, ok := f.Chars[r]; ok {
+		return c, true
+	}
+	c, ok := f.Chars[fallbackChar]
+	return c, ok
+}
@@ -0,0 +1,128 @@
+package bmfont
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxPages is the most pages a font can use, as chars store their page in a byte
+const maxPages = 256
+
+func parseText(s *bufio.Scanner) (*Font, error) {
+	f := newFont()
+	line := 0
+	for s.Scan() {
+		line++
+		tag, attrs := splitLine(s.Text())
+		if err := f.applyTag(tag, attrs); err != nil {
+			return nil, fmt.Errorf("bmfont line %d: %v", line, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *Font) applyTag(tag string, attrs map[string]string) error {
+	var err error
+	num := func(key string) int {
+		v, ok := attrs[key]
+		if !ok || err != nil {
+			return 0
+		}
+		var n int
+		n, err = strconv.Atoi(v)
+		return n
+	}
+
+	switch tag {
+	case "info":
+		f.Face = attrs["face"]
+		f.Size = num("size")
+	case "common":
+		f.LineHeight = num("lineHeight")
+		f.Base = num("base")
+		f.ScaleW = num("scaleW")
+		f.ScaleH = num("scaleH")
+		pages := num("pages")
+		if err == nil && (pages < 0 || pages > maxPages) {
+			return fmt.Errorf("invalid page count %d", pages)
+		}
+		for len(f.Pages) < pages {
+			f.Pages = append(f.Pages, "")
+		}
+	case "page":
+		// ids index the pages= count from common, or follow on one at a time
+		// when a font leaves it out
+		id := num("id")
+		if err != nil {
+			break
+		}
+		if id < 0 || id > len(f.Pages) || id >= maxPages {
+			return fmt.Errorf("page id %d is outside the %d pages declared", id, len(f.Pages))
+		}
+		if id == len(f.Pages) {
+			f.Pages = append(f.Pages, "")
+		}
+		f.Pages[id] = attrs["file"]
+	case "char":
+		c := Char{
+			ID:       rune(num("id")),
+			X:        num("x"),
+			Y:        num("y"),
+			Width:    num("width"),
+			Height:   num("height"),
+			XOffset:  num("xoffset"),
+			YOffset:  num("yoffset"),
+			XAdvance: num("xadvance"),
+			Page:     num("page"),
+			Channel:  num("chnl"),
+		}
+		f.Chars[c.ID] = c
+	case "kerning":
+		pair := KerningPair{First: rune(num("first")), Second: rune(num("second"))}
+		f.Kerning[pair] = num("amount")
+	}
+	return err
+}
+
+// splitLine breaks a line such as `page id=0 file="font 0.png"` into its tag
+// and key/value attributes, honouring quoted values
+func splitLine(line string) (string, map[string]string) {
+	line = strings.TrimSpace(line)
+	tag := line
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		tag, line = line[:i], line[i+1:]
+	} else {
+		line = ""
+	}
+
+	attrs := map[string]string{}
+	for {
+		line = strings.TrimLeft(line, " \t")
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return tag, attrs
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				value, line = line[1:], ""
+			} else {
+				value, line = line[1:end+1], line[end+2:]
+			}
+		} else if end := strings.IndexAny(line, " \t"); end >= 0 {
+			value, line = line[:end], line[end:]
+		} else {
+			value, line = line, ""
+		}
+		attrs[key] = value
+	}
+}
@@ -0,0 +1,201 @@
+package capture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testColors = []color.RGBA{
+	{255, 0, 0, 255},
+	{0, 255, 0, 255},
+	{0, 0, 255, 255},
+	{255, 255, 255, 255},
+}
+
+// stripes is a w by h image with a vertical stripe of each test color, rotated
+// by shift so every frame differs
+func stripes(w, h, shift int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		c := testColors[(x*len(testColors)/w+shift)%len(testColors)]
+		draw.Draw(img, image.Rect(x, 0, x+1, h), &image.Uniform{C: c}, image.Point{}, draw.Src)
+	}
+	return img
+}
+
+func TestWriteGIF(t *testing.T) {
+	frames := []image.Image{stripes(8, 4, 0), stripes(8, 4, 1), stripes(8, 4, 2)}
+	tests := []struct {
+		name   string
+		delays []time.Duration
+		opts   GIFOptions
+		delay  []int
+		loop   int
+	}{
+		{"default delay", nil, GIFOptions{}, []int{3, 3, 3}, -1},
+		{"last delay repeats", []time.Duration{100 * time.Millisecond, 40 * time.Millisecond}, GIFOptions{Loop: true}, []int{10, 4, 4}, 0},
+		{"short delays are raised to 2", []time.Duration{time.Millisecond}, GIFOptions{Colors: 4, Dither: true}, []int{2, 2, 2}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteGIF(&buf, frames, tt.delays, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			anim, err := gif.DecodeAll(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(anim.Delay, tt.delay) {
+				t.Errorf("delays %v, want %v", anim.Delay, tt.delay)
+			}
+			if anim.LoopCount != tt.loop {
+				t.Errorf("loop count %d, want %d", anim.LoopCount, tt.loop)
+			}
+			if len(anim.Image) != len(frames) {
+				t.Fatalf("got %d frames, want %d", len(anim.Image), len(frames))
+			}
+			// four flat colors fit the palette, so every pixel survives exactly
+			for i, p := range anim.Image {
+				for y := 0; y < 4; y++ {
+					for x := 0; x < 8; x++ {
+						want := frames[i].At(x, y)
+						if r, g, b, a := p.At(x, y).RGBA(); !reflect.DeepEqual([4]uint32{r, g, b, a}, rgba(want)) {
+							t.Fatalf("frame %d pixel %d,%d = %v, want %v", i, x, y, p.At(x, y), want)
+						}
+					}
+				}
+			}
+		})
+	}
+
+	if err := WriteGIF(&bytes.Buffer{}, nil, nil, GIFOptions{}); err != errNoFrames {
+		t.Errorf("got %v for no frames, want %v", err, errNoFrames)
+	}
+}
+
+func rgba(c color.Color) [4]uint32 {
+	r, g, b, a := c.RGBA()
+	return [4]uint32{r, g, b, a}
+}
+
+func TestQuantize(t *testing.T) {
+	gradient := image.NewRGBA(image.Rect(0, 0, 256, 4))
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 4; y++ {
+			gradient.SetRGBA(x, y, color.RGBA{uint8(x), uint8(255 - x), uint8(y * 60), 255})
+		}
+	}
+
+	tests := []struct {
+		name   string
+		images []image.Image
+		n      int
+		want   int
+	}{
+		{"exact colors", []image.Image{stripes(8, 2, 0)}, 16, len(testColors)},
+		{"limited to n", []image.Image{gradient}, 16, 16},
+		{"single color", []image.Image{image.NewGray(image.Rect(0, 0, 4, 4))}, 8, 1},
+		{"empty image", []image.Image{image.NewRGBA(image.Rect(0, 0, 0, 0))}, 8, 1},
+		{"no images", nil, 8, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if pal := Quantize(tt.images, tt.n); len(pal) != tt.want {
+				t.Errorf("palette has %d colors, want %d", len(pal), tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantizeSamples(t *testing.T) {
+	// 64 frames of 256x256 would be 4M pixels without the sample budget
+	frames := make([]image.Image, 64)
+	for i := range frames {
+		frames[i] = stripes(256, 256, i)
+	}
+	var pixels [][3]uint8
+	for _, f := range frames {
+		pixels = appendSamples(pixels, f, maxSamples/len(frames))
+	}
+	if len(pixels) > maxSamples {
+		t.Errorf("sampled %d pixels, want at most %d", len(pixels), maxSamples)
+	}
+	if pal := Quantize(frames, 256); len(pal) != len(testColors) {
+		t.Errorf("palette has %d colors, want %d", len(pal), len(testColors))
+	}
+}
+
+func TestWritePPM(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(1, 1, 3, 2))
+	img.Set(1, 1, color.NRGBA{10, 20, 30, 255})
+	img.Set(2, 1, color.NRGBA{40, 50, 60, 255})
+
+	var buf bytes.Buffer
+	if err := WritePPM(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte("P6\n2 1\n255\n"), 10, 20, 30, 40, 50, 60)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("got %q, want %q", buf.Bytes(), want)
+	}
+}
+
+func TestWriteSequence(t *testing.T) {
+	frames := []image.Image{stripes(4, 2, 0), stripes(4, 2, 1)}
+	tests := []struct {
+		format SequenceFormat
+		ext    string
+	}{
+		{SequencePNG, ".png"},
+		{SequencePPM, ".ppm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "frames")
+			files, err := WriteSequence(dir, "shot", tt.format, frames)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{filepath.Join(dir, "shot-0001"+tt.ext), filepath.Join(dir, "shot-0002"+tt.ext)}
+			if !reflect.DeepEqual(files, want) {
+				t.Fatalf("wrote %v, want %v", files, want)
+			}
+
+			for i, file := range files {
+				data, err := ioutil.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var expected bytes.Buffer
+				if tt.format == SequencePPM {
+					WritePPM(&expected, frames[i])
+					if !bytes.Equal(data, expected.Bytes()) {
+						t.Errorf("%s does not match WritePPM", file)
+					}
+					continue
+				}
+				img, err := png.Decode(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(img, frames[i]) {
+					t.Errorf("%s does not decode to the frame written", file)
+				}
+			}
+		})
+	}
+
+	if _, err := WriteSequence(t.TempDir(), "shot", SequencePNG, nil); err != errNoFrames {
+		t.Errorf("got %v for no frames, want %v", err, errNoFrames)
+	}
+}
@@ -0,0 +1,182 @@
+package capture
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sort"
+	"time"
+)
+
+var errNoFrames = errors.New("capture: no frames to encode")
+
+type GIFOptions struct {
+	Colors int  // palette size, at most 256, defaults to 256
+	Dither bool // Floyd-Steinberg dithering against the palette
+	Loop   bool // repeat forever instead of playing once
+}
+
+// WriteGIF encodes frames as an animated GIF sharing one palette quantized
+// from every frame, so colors do not flicker between frames. delays holds how
+// long each frame is shown; the last delay is repeated if there are fewer.
+func WriteGIF(w io.Writer, frames []image.Image, delays []time.Duration, opts GIFOptions) error {
+	if len(frames) == 0 {
+		return errNoFrames
+	}
+	if opts.Colors <= 0 || opts.Colors > 256 {
+		opts.Colors = 256
+	}
+
+	pal := Quantize(frames, opts.Colors)
+
+	var drawer draw.Drawer = draw.Src
+	if opts.Dither {
+		drawer = draw.FloydSteinberg
+	}
+
+	anim := &gif.GIF{LoopCount: -1}
+	if opts.Loop {
+		anim.LoopCount = 0
+	}
+	for i, frame := range frames {
+		b := frame.Bounds()
+		p := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), pal)
+		drawer.Draw(p, p.Bounds(), frame, b.Min)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, centiseconds(delayFor(delays, i)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+func delayFor(delays []time.Duration, i int) time.Duration {
+	if len(delays) == 0 {
+		return time.Second / 30
+	}
+	if i >= len(delays) {
+		return delays[len(delays)-1]
+	}
+	return delays[i]
+}
+
+func centiseconds(d time.Duration) int {
+	cs := int((d + 5*time.Millisecond) / (10 * time.Millisecond))
+	// browsers treat delays under 2 as 10, so 2 is the fastest that plays as asked
+	if cs < 2 {
+		return 2
+	}
+	return cs
+}
+
+// maxSamples caps the pixels median cut looks at across all images, so
+// quantizing a long recording costs about the same as a short one
+const maxSamples = 1 << 18
+
+// Quantize picks a palette of at most n colors for all images using median
+// cut. It returns an empty palette when there are no images.
+func Quantize(images []image.Image, n int) color.Palette {
+	if len(images) == 0 {
+		return nil
+	}
+	budget := maxSamples / len(images)
+	if budget < 1 {
+		budget = 1
+	}
+	var pixels [][3]uint8
+	for _, img := range images {
+		pixels = appendSamples(pixels, img, budget)
+	}
+	if len(pixels) == 0 {
+		return color.Palette{color.Black}
+	}
+
+	boxes := []colorBox{newColorBox(pixels)}
+	for len(boxes) < n {
+		// split the box with the widest channel range
+		widest, widestRange := -1, 0
+		for i, b := range boxes {
+			if len(b.pixels) >= 2 && b.width > widestRange {
+				widest, widestRange = i, b.width
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		a, b := boxes[widest].split()
+		boxes[widest] = a
+		boxes = append(boxes, b)
+	}
+
+	pal := make(color.Palette, len(boxes))
+	for i, b := range boxes {
+		pal[i] = b.average()
+	}
+	return pal
+}
+
+// appendSamples adds up to about budget pixels from img, skipping evenly on
+// large images
+func appendSamples(pixels [][3]uint8, img image.Image, budget int) [][3]uint8 {
+	b := img.Bounds()
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > budget {
+		step++
+	}
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			pixels = append(pixels, [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(bl >> 8)})
+		}
+	}
+	return pixels
+}
+
+// colorBox is a set of pixels with its widest channel and that channel's
+// range worked out once when the box is made
+type colorBox struct {
+	pixels  [][3]uint8
+	channel int
+	width   int
+}
+
+func newColorBox(pixels [][3]uint8) colorBox {
+	lo := [3]uint8{255, 255, 255}
+	var hi [3]uint8
+	for _, p := range pixels {
+		for c := 0; c < 3; c++ {
+			if p[c] < lo[c] {
+				lo[c] = p[c]
+			}
+			if p[c] > hi[c] {
+				hi[c] = p[c]
+			}
+		}
+	}
+	b := colorBox{pixels: pixels, width: -1}
+	for c := 0; c < 3; c++ {
+		if r := int(hi[c]) - int(lo[c]); r > b.width {
+			b.channel, b.width = c, r
+		}
+	}
+	return b
+}
+
+func (b colorBox) split() (colorBox, colorBox) {
+	c := b.channel
+	sort.Slice(b.pixels, func(i, j int) bool { return b.pixels[i][c] < b.pixels[j][c] })
+	mid := len(b.pixels) / 2
+	return newColorBox(b.pixels[:mid]), newColorBox(b.pixels[mid:])
+}
+
+func (b colorBox) average() color.Color {
+	var sum [3]int
+	for _, p := range b.pixels {
+		for c := 0; c < 3; c++ {
+			sum[c] += int(p[c])
+		}
+	}
+	n := len(b.pixels)
+	return color.RGBA{R: uint8(sum[0] / n), G: uint8(sum[1] / n), B: uint8(sum[2] / n), A: 255}
+}
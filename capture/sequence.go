@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type SequenceFormat int
+
+const (
+	SequencePNG SequenceFormat = iota
+	SequencePPM
+)
+
+func (f SequenceFormat) extension() string {
+	if f == SequencePPM {
+		return ".ppm"
+	}
+	return ".png"
+}
+
+// WriteSequence writes each frame to dir as <prefix>-0001.png, <prefix>-0002.png
+// and so on, or .ppm, returning the files written
+func WriteSequence(dir, prefix string, format SequenceFormat, frames []image.Image) ([]string, error) {
+	if len(frames) == 0 {
+		return nil, errNoFrames
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for i, frame := range frames {
+		file := filepath.Join(dir, fmt.Sprintf("%s-%04d%s", prefix, i+1, format.extension()))
+		if err := writeFrame(file, format, frame); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func writeFrame(file string, format SequenceFormat, img image.Image) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if format == SequencePPM {
+		err = WritePPM(f, img)
+	} else {
+		err = png.Encode(f, img)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WritePPM encodes img as a binary (P6) PPM, dropping alpha
+func WritePPM(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P6\n%d %d\n255\n", b.Dx(), b.Dy()); err != nil {
+		return err
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			if _, err := bw.Write([]byte{uint8(r >> 8), uint8(g >> 8), uint8(bl >> 8)}); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,120 @@
+package compressed
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+)
+
+// Decode expands a level to RGBA in software, for drivers without the
+// matching compression extension. Only BC1, BC3 and uncompressed RGBA8 are
+// supported.
+func (l Level) Decode(format Format) (*image.RGBA, error) {
+	switch format {
+	case FormatRGBA8, FormatBC1, FormatBC3:
+	default:
+		return nil, fmt.Errorf("compressed: no software decoder for %v", format)
+	}
+	// check the size before allocating, which LevelSize bounds to MaxDimension
+	size, err := format.LevelSize(l.Width, l.Height)
+	if err != nil {
+		return nil, err
+	}
+	if size > len(l.Data) {
+		return nil, errTruncated
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, l.Width, l.Height))
+	if format == FormatRGBA8 {
+		copy(img.Pix, l.Data)
+		return img, nil
+	}
+
+	blockBytes := format.BlockBytes()
+	blocksWide := (l.Width + 3) / 4
+	var block [16][4]byte
+	for by := 0; by < (l.Height+3)/4; by++ {
+		for bx := 0; bx < blocksWide; bx++ {
+			src := l.Data[(by*blocksWide+bx)*blockBytes:]
+			if format == FormatBC1 {
+				decodeColorBlock(src, &block, true)
+			} else {
+				decodeColorBlock(src[8:], &block, false)
+				decodeAlphaBlock(src, &block)
+			}
+			writeBlock(img, bx*4, by*4, &block)
+		}
+	}
+	return img, nil
+}
+
+// decodeColorBlock expands an 8 byte BC1 color block. BC2 and BC3 color blocks
+// always use four colors, BC1 drops to three plus transparent black when the
+// first endpoint is not greater than the second.
+func decodeColorBlock(src []byte, block *[16][4]byte, bc1 bool) {
+	c0, c1 := binary.LittleEndian.Uint16(src), binary.LittleEndian.Uint16(src[2:])
+	indices := binary.LittleEndian.Uint32(src[4:])
+
+	var palette [4][4]byte
+	palette[0] = rgb565(c0)
+	palette[1] = rgb565(c1)
+	if c0 > c1 || !bc1 {
+		for i := 0; i < 3; i++ {
+			palette[2][i] = byte((2*int(palette[0][i]) + int(palette[1][i])) / 3)
+			palette[3][i] = byte((int(palette[0][i]) + 2*int(palette[1][i])) / 3)
+		}
+		palette[2][3], palette[3][3] = 255, 255
+	} else {
+		for i := 0; i < 3; i++ {
+			palette[2][i] = byte((int(palette[0][i]) + int(palette[1][i])) / 2)
+		}
+		palette[2][3] = 255
+		palette[3] = [4]byte{}
+	}
+
+	for p := 0; p < 16; p++ {
+		block[p] = palette[(indices>>(2*uint(p)))&3]
+	}
+}
+
+// decodeAlphaBlock fills in alpha from the 8 byte BC3 alpha block
+func decodeAlphaBlock(src []byte, block *[16][4]byte) {
+	a0, a1 := int(src[0]), int(src[1])
+
+	var alphas [8]int
+	alphas[0], alphas[1] = a0, a1
+	if a0 > a1 {
+		for i := 1; i < 7; i++ {
+			alphas[i+1] = ((7-i)*a0 + i*a1) / 7
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			alphas[i+1] = ((5-i)*a0 + i*a1) / 5
+		}
+		alphas[6], alphas[7] = 0, 255
+	}
+
+	// 16 three bit indices packed little endian into six bytes
+	var bits uint64
+	for i := 7; i >= 2; i-- {
+		bits = bits<<8 | uint64(src[i])
+	}
+	for p := 0; p < 16; p++ {
+		block[p][3] = byte(alphas[(bits>>(3*uint(p)))&7])
+	}
+}
+
+func rgb565(c uint16) [4]byte {
+	r, g, b := byte(c>>11&0x1f), byte(c>>5&0x3f), byte(c&0x1f)
+	return [4]byte{r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2, 255}
+}
+
+func writeBlock(img *image.RGBA, x0, y0 int, block *[16][4]byte) {
+	for p := 0; p < 16; p++ {
+		x, y := x0+p%4, y0+p/4
+		if x >= img.Rect.Dx() || y >= img.Rect.Dy() {
+			continue
+		}
+		copy(img.Pix[img.PixOffset(x, y):], block[p][:])
+	}
+}
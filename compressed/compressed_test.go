@@ -0,0 +1,248 @@
+package compressed
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// testLevels is an 8x8 BC1 mip chain with every byte of each level set to its index
+func testLevels() []Level {
+	sizes := [][2]int{{8, 8}, {4, 4}, {2, 2}, {1, 1}}
+	levels := make([]Level, len(sizes))
+	for i, s := range sizes {
+		size, _ := FormatBC1.LevelSize(s[0], s[1])
+		data := bytes.Repeat([]byte{byte(i + 1)}, size)
+		levels[i] = Level{Width: s[0], Height: s[1], Data: data}
+	}
+	return levels
+}
+
+func testTexture(srgb bool) *Texture {
+	return &Texture{Format: FormatBC1, SRGB: srgb, Width: 8, Height: 8, Levels: testLevels()}
+}
+
+func ktx1(order binary.ByteOrder, internalFormat uint32, faces uint32, levels []Level) []byte {
+	header := make([]byte, ktx1HeaderSize)
+	copy(header, ktx1Identifier)
+	order.PutUint32(header[12:], 0x04030201)
+	kv := []byte("key\x00value\x00\x00\x00")
+	fields := []uint32{0, 1, 0, internalFormat, 0, uint32(levels[0].Width), uint32(levels[0].Height), 0, 0, faces, uint32(len(levels)), uint32(len(kv))}
+	for i, f := range fields {
+		order.PutUint32(header[16+i*4:], f)
+	}
+
+	out := append(header, kv...)
+	for _, l := range levels {
+		size := make([]byte, 4)
+		order.PutUint32(size, uint32(len(l.Data)))
+		out = append(out, size...)
+		out = append(out, l.Data...)
+		out = append(out, make([]byte, (4-len(l.Data)%4)%4)...)
+	}
+	return out
+}
+
+// ktx2 stores the levels smallest first, as the spec recommends, behind a
+// largest first index
+func ktx2(vkFormat uint32, layers uint32, levels []Level) []byte {
+	le := binary.LittleEndian
+	header := make([]byte, ktx2HeaderSize+24*len(levels))
+	copy(header, ktx2Identifier)
+	le.PutUint32(header[12:], vkFormat)
+	le.PutUint32(header[20:], uint32(levels[0].Width))
+	le.PutUint32(header[24:], uint32(levels[0].Height))
+	le.PutUint32(header[32:], layers)
+	le.PutUint32(header[36:], 1)
+	le.PutUint32(header[40:], uint32(len(levels)))
+
+	out := header
+	for i := len(levels) - 1; i >= 0; i-- {
+		entry := out[ktx2HeaderSize+i*24:]
+		le.PutUint64(entry, uint64(len(out)))
+		le.PutUint64(entry[8:], uint64(len(levels[i].Data)))
+		le.PutUint64(entry[16:], uint64(len(levels[i].Data)))
+		out = append(out, levels[i].Data...)
+	}
+	return out
+}
+
+// dds writes a header with the given pixel format block (flags, FourCC, bit
+// count and masks) followed by the optional DX10 header and the levels
+func dds(pixelFormat []uint32, caps2 uint32, dx10 []uint32, width, height int, levels []Level) []byte {
+	le := binary.LittleEndian
+	header := make([]byte, ddsHeaderSize)
+	le.PutUint32(header[0:], ddsHeaderSize)
+	le.PutUint32(header[8:], uint32(height))
+	le.PutUint32(header[12:], uint32(width))
+	le.PutUint32(header[24:], uint32(len(levels)))
+	le.PutUint32(header[ddsPixelFormatBase:], 32)
+	for i, v := range pixelFormat {
+		le.PutUint32(header[ddsPixelFormatBase+4+i*4:], v)
+	}
+	le.PutUint32(header[108:], caps2)
+
+	out := append([]byte("DDS "), header...)
+	for _, v := range dx10 {
+		out = append(out, 0, 0, 0, 0)
+		le.PutUint32(out[len(out)-4:], v)
+	}
+	for _, l := range levels {
+		out = append(out, l.Data...)
+	}
+	return out
+}
+
+func fourCC(s string) uint32 {
+	return binary.LittleEndian.Uint32([]byte(s))
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want *Texture
+	}{
+		{"ktx1 little endian", ktx1(binary.LittleEndian, 0x83F1, 1, testLevels()), testTexture(false)},
+		{"ktx1 big endian", ktx1(binary.BigEndian, 0x83F1, 1, testLevels()), testTexture(false)},
+		{"ktx1 srgb", ktx1(binary.LittleEndian, 0x8C4D, 1, testLevels()), testTexture(true)},
+		{"ktx2", ktx2(131, 0, testLevels()), testTexture(false)},
+		{"ktx2 srgb", ktx2(132, 0, testLevels()), testTexture(true)},
+		{"dds fourcc", dds([]uint32{ddsFlagFourCC, fourCC("DXT1")}, 0, nil, 8, 8, testLevels()), testTexture(false)},
+		{"dds dx10", dds([]uint32{ddsFlagFourCC, fourCC("DX10")}, 0, []uint32{72, ddsDimTexture2D, 0, 1, 0}, 8, 8, testLevels()), testTexture(true)},
+		{
+			"dds bgra",
+			dds([]uint32{ddsFlagRGB, 0, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0xff000000}, 0, nil, 2, 1,
+				[]Level{{Width: 2, Height: 1, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8}}}),
+			&Texture{Format: FormatRGBA8, Width: 2, Height: 1,
+				Levels: []Level{{Width: 2, Height: 1, Data: []byte{3, 2, 1, 4, 7, 6, 5, 8}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	le := binary.LittleEndian
+	hugeOffset := ktx2(131, 0, testLevels())
+	le.PutUint64(hugeOffset[ktx2HeaderSize:], 1<<63)
+	hugeSize := ktx2(131, 0, testLevels())
+	le.PutUint64(hugeSize[ktx2HeaderSize+8:], ^uint64(0))
+	full := ktx1(binary.LittleEndian, 0x83F1, 1, testLevels())
+	short := testLevels()
+	short[1].Data = short[1].Data[:4]
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"unknown container", []byte("PNG\r\n")},
+		{"ktx1 header", ktx1Identifier},
+		{"ktx1 truncated level", full[:len(full)-4]},
+		{"ktx1 unknown format", ktx1(binary.LittleEndian, 0x1234, 1, testLevels())},
+		{"ktx1 cube map", ktx1(binary.LittleEndian, 0x83F1, 6, testLevels())},
+		{"ktx1 short level", ktx1(binary.LittleEndian, 0x83F1, 1, short)},
+		{"ktx2 short level", ktx2(131, 0, short)},
+		{"ktx2 array", ktx2(131, 2, testLevels())},
+		{"ktx2 basis", ktx2(0, 0, testLevels())},
+		{"ktx2 level offset past the end", hugeOffset},
+		{"ktx2 level size wraps", hugeSize},
+		{"dds header", []byte("DDS \x7c")},
+		{"dds cube map", dds([]uint32{ddsFlagFourCC, fourCC("DXT1")}, ddsCaps2Cubemap, nil, 8, 8, testLevels())},
+		{"dds unknown fourcc", dds([]uint32{ddsFlagFourCC, fourCC("ABCD")}, 0, nil, 8, 8, testLevels())},
+		{"dds truncated levels", dds([]uint32{ddsFlagFourCC, fourCC("DXT1")}, 0, nil, 8, 8, testLevels()[:1])[:100]},
+		{"dds oversized", dds([]uint32{ddsFlagFourCC, fourCC("DXT5")}, 0, nil, 0xC0000000, 0xC0000000, testLevels())},
+		{"dds zero width", dds([]uint32{ddsFlagFourCC, fourCC("DXT1")}, 0, nil, 0, 8, testLevels())},
+		{"dds dx10 array", dds([]uint32{ddsFlagFourCC, fourCC("DX10")}, 0, []uint32{71, ddsDimTexture2D, 0, 4, 0}, 8, 8, testLevels())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(bytes.NewReader(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLevelSize(t *testing.T) {
+	tests := []struct {
+		format        Format
+		width, height int
+		want          int
+	}{
+		{FormatRGBA8, 3, 5, 60},
+		{FormatBC1, 1, 1, 8},
+		{FormatBC1, 5, 4, 16},
+		{FormatBC3, 8, 8, 64},
+		{FormatBC7, 6, 6, 64},
+		{FormatETC2RGB, 4, 12, 24},
+	}
+	for _, tt := range tests {
+		got, err := tt.format.LevelSize(tt.width, tt.height)
+		if err != nil || got != tt.want {
+			t.Errorf("%v.LevelSize(%d, %d) = %d, %v, want %d", tt.format, tt.width, tt.height, got, err, tt.want)
+		}
+	}
+
+	for _, size := range [][2]int{{0, 4}, {4, -1}, {MaxDimension + 1, 4}, {4, 0xC0000000}} {
+		if _, err := FormatBC3.LevelSize(size[0], size[1]); err == nil {
+			t.Errorf("LevelSize(%d, %d) accepted an invalid size", size[0], size[1])
+		}
+	}
+}
+
+func TestSoftwareDecode(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	// red and blue endpoints; each row of the block uses one palette index
+	fourColor := []byte{0x00, 0xf8, 0x1f, 0x00, 0x00, 0x55, 0xaa, 0xff}
+	threeColor := []byte{0x1f, 0x00, 0x00, 0xf8, 0x00, 0x55, 0xaa, 0xff}
+	// alpha 255 to 0 in eight steps, every pixel on the second index
+	alpha := []byte{0xff, 0x00, 0x49, 0x92, 0x24, 0x49, 0x92, 0x24}
+
+	tests := []struct {
+		name   string
+		format Format
+		data   []byte
+		rows   [4]color.RGBA
+	}{
+		{"bc1 four colours", FormatBC1, fourColor, [4]color.RGBA{red, blue, {170, 0, 85, 255}, {85, 0, 170, 255}}},
+		{"bc1 transparent", FormatBC1, threeColor, [4]color.RGBA{blue, red, {127, 0, 127, 255}, {}}},
+		{"bc3", FormatBC3, append(alpha, fourColor...), [4]color.RGBA{{255, 0, 0, 0}, {0, 0, 255, 0}, {170, 0, 85, 0}, {85, 0, 170, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Level{Width: 4, Height: 4, Data: tt.data}.Decode(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for y := 0; y < 4; y++ {
+				for x := 0; x < 4; x++ {
+					if got := img.RGBAAt(x, y); got != tt.rows[y] {
+						t.Errorf("pixel %d,%d = %v, want %v", x, y, got, tt.rows[y])
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := (Level{Width: 4, Height: 4, Data: fourColor[:4]}).Decode(FormatBC1); err == nil {
+		t.Error("expected an error for a truncated level")
+	}
+	if _, err := (Level{Width: 0xC0000000, Height: 0xC0000000, Data: fourColor}).Decode(FormatBC1); err == nil {
+		t.Error("expected an error for an oversized level")
+	}
+	if _, err := (Level{Width: 4, Height: 4, Data: make([]byte, 16)}).Decode(FormatBC7); err == nil {
+		t.Error("expected an error for a format without a software decoder")
+	}
+}
@@ -0,0 +1,124 @@
+package compressed
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+var ddsMagic = []byte("DDS ")
+
+const (
+	ddsHeaderSize      = 124
+	ddsDX10HeaderSize  = 20
+	ddsPixelFormatBase = 72
+
+	ddsFlagFourCC   = 0x4
+	ddsFlagRGB      = 0x40
+	ddsCaps2Cubemap = 0x200
+	ddsCaps2Volume  = 0x200000
+	ddsDimTexture2D = 3
+)
+
+var ddsFourCCs = map[string]Format{
+	"DXT1": FormatBC1,
+	"DXT2": FormatBC2,
+	"DXT3": FormatBC2,
+	"DXT4": FormatBC3,
+	"DXT5": FormatBC3,
+	"ATI1": FormatBC4,
+	"BC4U": FormatBC4,
+	"ATI2": FormatBC5,
+	"BC5U": FormatBC5,
+}
+
+// DXGI formats found in DX10 extended headers
+var dxgiFormats = map[uint32]glFormat{
+	28: {FormatRGBA8, false},
+	29: {FormatRGBA8, true},
+	71: {FormatBC1, false},
+	72: {FormatBC1, true},
+	74: {FormatBC2, false},
+	75: {FormatBC2, true},
+	77: {FormatBC3, false},
+	78: {FormatBC3, true},
+	80: {FormatBC4, false},
+	83: {FormatBC5, false},
+	95: {FormatBC6HUnsigned, false},
+	96: {FormatBC6HSigned, false},
+	98: {FormatBC7, false},
+	99: {FormatBC7, true},
+}
+
+func parseDDS(data []byte) (*Texture, error) {
+	data = data[len(ddsMagic):]
+	if len(data) < ddsHeaderSize {
+		return nil, errTruncated
+	}
+	le := binary.LittleEndian
+
+	height, width := int(le.Uint32(data[8:])), int(le.Uint32(data[12:]))
+	// some writers fill in the mip count without setting its flag, so trust the count
+	levelCount := int(le.Uint32(data[24:]))
+	if caps2 := le.Uint32(data[108:]); caps2&(ddsCaps2Cubemap|ddsCaps2Volume) != 0 {
+		return nil, errNotFlat2D
+	}
+
+	pf := data[ddsPixelFormatBase:]
+	pfFlags := le.Uint32(pf[4:])
+	fourCC := string(pf[8:12])
+	body := data[ddsHeaderSize:]
+
+	t := &Texture{Width: width, Height: height}
+	swizzleBGRA := false
+
+	switch {
+	case pfFlags&ddsFlagFourCC != 0 && fourCC == "DX10":
+		if len(body) < ddsDX10HeaderSize {
+			return nil, errTruncated
+		}
+		dxgi, dimension, arraySize := le.Uint32(body), le.Uint32(body[4:]), le.Uint32(body[12:])
+		f, ok := dxgiFormats[dxgi]
+		if !ok {
+			return nil, fmt.Errorf("compressed: unsupported DXGI format %d", dxgi)
+		}
+		if dimension != ddsDimTexture2D || arraySize > 1 {
+			return nil, errNotFlat2D
+		}
+		t.Format, t.SRGB = f.format, f.srgb
+		body = body[ddsDX10HeaderSize:]
+	case pfFlags&ddsFlagFourCC != 0:
+		f, ok := ddsFourCCs[fourCC]
+		if !ok {
+			return nil, fmt.Errorf("compressed: unsupported DDS FourCC %q", fourCC)
+		}
+		t.Format = f
+	case pfFlags&ddsFlagRGB != 0 && le.Uint32(pf[12:]) == 32:
+		rMask, bMask := le.Uint32(pf[16:]), le.Uint32(pf[24:])
+		switch {
+		case rMask == 0x000000ff && bMask == 0x00ff0000:
+		case rMask == 0x00ff0000 && bMask == 0x000000ff:
+			swizzleBGRA = true
+		default:
+			return nil, fmt.Errorf("compressed: unsupported DDS channel masks R=%08x B=%08x", rMask, bMask)
+		}
+		t.Format = FormatRGBA8
+	default:
+		return nil, fmt.Errorf("compressed: unsupported DDS pixel format flags 0x%x", pfFlags)
+	}
+
+	levels, err := mipLevels(body, t.Format, width, height, levelCount)
+	if err != nil {
+		return nil, err
+	}
+	if swizzleBGRA {
+		for i := range levels {
+			pix := append([]byte(nil), levels[i].Data...)
+			for p := 0; p+3 < len(pix); p += 4 {
+				pix[p], pix[p+2] = pix[p+2], pix[p]
+			}
+			levels[i].Data = pix
+		}
+	}
+	t.Levels = levels
+	return t, nil
+}
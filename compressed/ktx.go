@@ -0,0 +1,198 @@
+package compressed
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var ktx1Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '1', '1', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+var errNotFlat2D = errors.New("compressed: only single 2D textures are supported, not arrays, cube maps or 3D")
+
+const (
+	ktx1HeaderSize = 64
+	ktx2HeaderSize = 80
+)
+
+type glFormat struct {
+	format Format
+	srgb   bool
+}
+
+// OpenGL internal formats found in KTX 1 files
+var ktx1Formats = map[uint32]glFormat{
+	0x8058: {FormatRGBA8, false}, // RGBA8
+	0x8C43: {FormatRGBA8, true},  // SRGB8_ALPHA8
+	0x83F0: {FormatBC1, false},   // COMPRESSED_RGB_S3TC_DXT1_EXT
+	0x83F1: {FormatBC1, false},   // COMPRESSED_RGBA_S3TC_DXT1_EXT
+	0x8C4C: {FormatBC1, true},    // COMPRESSED_SRGB_S3TC_DXT1_EXT
+	0x8C4D: {FormatBC1, true},    // COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT
+	0x83F2: {FormatBC2, false},   // COMPRESSED_RGBA_S3TC_DXT3_EXT
+	0x8C4E: {FormatBC2, true},    // COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT
+	0x83F3: {FormatBC3, false},   // COMPRESSED_RGBA_S3TC_DXT5_EXT
+	0x8C4F: {FormatBC3, true},    // COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT
+	0x8DBB: {FormatBC4, false},   // COMPRESSED_RED_RGTC1
+	0x8DBD: {FormatBC5, false},   // COMPRESSED_RG_RGTC2
+	0x8E8F: {FormatBC6HUnsigned, false},
+	0x8E8E: {FormatBC6HSigned, false},
+	0x8E8C: {FormatBC7, false},
+	0x8E8D: {FormatBC7, true},
+	0x9274: {FormatETC2RGB, false},
+	0x9275: {FormatETC2RGB, true},
+	0x9276: {FormatETC2RGBA1, false},
+	0x9277: {FormatETC2RGBA1, true},
+	0x9278: {FormatETC2RGBA, false},
+	0x9279: {FormatETC2RGBA, true},
+}
+
+// Vulkan formats found in KTX 2 files
+var ktx2Formats = map[uint32]glFormat{
+	37:  {FormatRGBA8, false},
+	43:  {FormatRGBA8, true},
+	131: {FormatBC1, false},
+	132: {FormatBC1, true},
+	133: {FormatBC1, false},
+	134: {FormatBC1, true},
+	135: {FormatBC2, false},
+	136: {FormatBC2, true},
+	137: {FormatBC3, false},
+	138: {FormatBC3, true},
+	139: {FormatBC4, false},
+	141: {FormatBC5, false},
+	143: {FormatBC6HUnsigned, false},
+	144: {FormatBC6HSigned, false},
+	145: {FormatBC7, false},
+	146: {FormatBC7, true},
+	147: {FormatETC2RGB, false},
+	148: {FormatETC2RGB, true},
+	149: {FormatETC2RGBA1, false},
+	150: {FormatETC2RGBA1, true},
+	151: {FormatETC2RGBA, false},
+	152: {FormatETC2RGBA, true},
+}
+
+func parseKTX1(data []byte) (*Texture, error) {
+	if len(data) < ktx1HeaderSize {
+		return nil, errTruncated
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.BigEndian.Uint32(data[12:16]) == 0x04030201 {
+		order = binary.BigEndian
+	}
+	field := func(i int) uint32 {
+		return order.Uint32(data[16+i*4:])
+	}
+
+	internalFormat := field(3)
+	width, height, depth := int(field(5)), int(field(6)), field(7)
+	arrayElements, faces, levelCount := field(8), field(9), int(field(10))
+	kvBytes := int(field(11))
+
+	f, ok := ktx1Formats[internalFormat]
+	if !ok {
+		return nil, fmt.Errorf("compressed: unsupported KTX internal format 0x%04X", internalFormat)
+	}
+	if depth > 1 || arrayElements > 0 || faces > 1 {
+		return nil, errNotFlat2D
+	}
+	if height == 0 {
+		height = 1
+	}
+	if levelCount == 0 {
+		levelCount = 1
+	}
+
+	t := &Texture{Format: f.format, SRGB: f.srgb, Width: width, Height: height}
+
+	offset := ktx1HeaderSize + kvBytes
+	w, h := width, height
+	for i := 0; i < levelCount; i++ {
+		if offset+4 > len(data) {
+			return nil, errTruncated
+		}
+		size := int(order.Uint32(data[offset:]))
+		offset += 4
+		if err := checkLevelSize(t.Format, w, h, uint64(size), i); err != nil {
+			return nil, err
+		}
+		if offset+size > len(data) {
+			return nil, errTruncated
+		}
+		t.Levels = append(t.Levels, Level{Width: w, Height: h, Data: data[offset : offset+size]})
+		// each level is padded to a four byte boundary
+		offset += (size + 3) &^ 3
+		w, h = halve(w), halve(h)
+	}
+	return t, nil
+}
+
+func parseKTX2(data []byte) (*Texture, error) {
+	if len(data) < ktx2HeaderSize {
+		return nil, errTruncated
+	}
+	le := binary.LittleEndian
+
+	vkFormat := le.Uint32(data[12:])
+	width, height, depth := int(le.Uint32(data[20:])), int(le.Uint32(data[24:])), le.Uint32(data[28:])
+	layers, faces, levelCount := le.Uint32(data[32:]), le.Uint32(data[36:]), int(le.Uint32(data[40:]))
+	supercompression := le.Uint32(data[44:])
+
+	if vkFormat == 0 {
+		return nil, errors.New("compressed: Basis Universal KTX 2 files are not supported")
+	}
+	f, ok := ktx2Formats[vkFormat]
+	if !ok {
+		return nil, fmt.Errorf("compressed: unsupported KTX 2 vkFormat %d", vkFormat)
+	}
+	if supercompression != 0 {
+		return nil, fmt.Errorf("compressed: unsupported KTX 2 supercompression scheme %d", supercompression)
+	}
+	if depth > 1 || layers > 0 || faces > 1 {
+		return nil, errNotFlat2D
+	}
+	if height == 0 {
+		height = 1
+	}
+	if levelCount == 0 {
+		levelCount = 1
+	}
+
+	t := &Texture{Format: f.format, SRGB: f.srgb, Width: width, Height: height}
+
+	// the level index follows the header, one 24 byte entry per level, largest first
+	index := data[ktx2HeaderSize:]
+	if len(index) < levelCount*24 {
+		return nil, errTruncated
+	}
+	w, h := width, height
+	for i := 0; i < levelCount; i++ {
+		entry := index[i*24:]
+		offset, size := le.Uint64(entry), le.Uint64(entry[8:])
+		if err := checkLevelSize(t.Format, w, h, size, i); err != nil {
+			return nil, err
+		}
+		if offset > uint64(len(data)) || size > uint64(len(data))-offset {
+			return nil, errTruncated
+		}
+		t.Levels = append(t.Levels, Level{Width: w, Height: h, Data: data[offset : offset+size]})
+		w, h = halve(w), halve(h)
+	}
+	return t, nil
+}
+
+// checkLevelSize makes sure a level's stored length is exactly what its size
+// and format need, so a short level never reaches the driver
+func checkLevelSize(format Format, width, height int, size uint64, level int) error {
+	want, err := format.LevelSize(width, height)
+	if err != nil {
+		return err
+	}
+	if size != uint64(want) {
+		return fmt.Errorf("compressed: level %d is %d bytes, a %dx%d %v level needs %d", level, size, width, height, format, want)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package compressed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+type Format int
+
+const (
+	FormatUnknown Format = iota
+	FormatRGBA8          // uncompressed, four bytes per pixel
+	FormatBC1            // DXT1, 1-bit alpha
+	FormatBC2            // DXT3
+	FormatBC3            // DXT5
+	FormatBC4
+	FormatBC5
+	FormatBC6HUnsigned
+	FormatBC6HSigned
+	FormatBC7
+	FormatETC2RGB
+	FormatETC2RGBA1
+	FormatETC2RGBA
+)
+
+var formatNames = map[Format]string{
+	FormatRGBA8:        "RGBA8",
+	FormatBC1:          "BC1",
+	FormatBC2:          "BC2",
+	FormatBC3:          "BC3",
+	FormatBC4:          "BC4",
+	FormatBC5:          "BC5",
+	FormatBC6HUnsigned: "BC6H_UF16",
+	FormatBC6HSigned:   "BC6H_SF16",
+	FormatBC7:          "BC7",
+	FormatETC2RGB:      "ETC2_RGB8",
+	FormatETC2RGBA1:    "ETC2_RGB8A1",
+	FormatETC2RGBA:     "ETC2_RGBA8",
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// BlockBytes is the size of one 4x4 block, or of one pixel for FormatRGBA8
+func (f Format) BlockBytes() int {
+	switch f {
+	case FormatRGBA8:
+		return 4
+	case FormatBC1, FormatBC4, FormatETC2RGB, FormatETC2RGBA1:
+		return 8
+	case FormatBC2, FormatBC3, FormatBC5, FormatBC6HUnsigned, FormatBC6HSigned, FormatBC7, FormatETC2RGBA:
+		return 16
+	}
+	return 0
+}
+
+func (f Format) Compressed() bool {
+	return f != FormatRGBA8 && f != FormatUnknown
+}
+
+// MaxDimension is the largest width or height accepted for a texture, the
+// limit of current GPUs
+const MaxDimension = 1 << 15
+
+const maxInt = int(^uint(0) >> 1)
+
+// LevelSize returns the number of bytes a width x height mip level occupies.
+// It fails for sizes outside 1 to MaxDimension and for levels too large to
+// address, which can only happen on 32-bit platforms.
+func (f Format) LevelSize(width, height int) (int, error) {
+	if width <= 0 || height <= 0 || width > MaxDimension || height > MaxDimension {
+		return 0, fmt.Errorf("compressed: invalid level size %dx%d", width, height)
+	}
+	w, h := uint64(width), uint64(height)
+	if f.Compressed() {
+		w, h = (w+3)/4, (h+3)/4
+	}
+	// at most 2^26 blocks of 16 bytes, so this cannot overflow a uint64
+	size := w * h * uint64(f.BlockBytes())
+	if size > uint64(maxInt) {
+		return 0, fmt.Errorf("compressed: %dx%d %v level is too large", width, height, f)
+	}
+	return int(size), nil
+}
+
+// Texture is a 2D mip chain read from a container file, largest level first
+type Texture struct {
+	Format Format
+	SRGB   bool
+	Width  int
+	Height int
+	Levels []Level
+}
+
+type Level struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+var errUnknownContainer = errors.New("compressed: not a KTX, KTX2 or DDS file")
+
+var errTruncated = errors.New("compressed: file is truncated")
+
+// Decode reads a KTX 1, KTX 2 or DDS file, detected from its header
+func Decode(r io.Reader) (*Texture, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(data, ktx1Identifier):
+		return parseKTX1(data)
+	case bytes.HasPrefix(data, ktx2Identifier):
+		return parseKTX2(data)
+	case bytes.HasPrefix(data, ddsMagic):
+		return parseDDS(data)
+	}
+	return nil, errUnknownContainer
+}
+
+// mipLevels slices data into consecutive, tightly packed mip levels
+func mipLevels(data []byte, format Format, width, height, count int) ([]Level, error) {
+	if count < 1 {
+		count = 1
+	}
+	var levels []Level
+	for i := 0; i < count; i++ {
+		size, err := format.LevelSize(width, height)
+		if err != nil {
+			return nil, err
+		}
+		if size > len(data) {
+			return nil, errTruncated
+		}
+		levels = append(levels, Level{Width: width, Height: height, Data: data[:size]})
+		data = data[size:]
+		width, height = halve(width), halve(height)
+	}
+	return levels, nil
+}
+
+func halve(v int) int {
+	if v > 1 {
+		return v / 2
+	}
+	return 1
+}
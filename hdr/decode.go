@@ -0,0 +1,214 @@
+package hdr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+)
+
+var errNotRadiance = errors.New("hdr: missing #?RADIANCE header")
+
+var errBadScanline = errors.New("hdr: corrupt scanline")
+
+// maxPixels bounds the images Decode will allocate, 16384x8192 or 2GB of
+// float32 pixels
+const maxPixels = 1 << 27
+
+func init() {
+	image.RegisterFormat("hdr", "#?", func(r io.Reader) (image.Image, error) {
+		return Decode(r)
+	}, func(r io.Reader) (image.Config, error) {
+		return DecodeConfig(r)
+	})
+}
+
+type header struct {
+	width    int
+	height   int
+	flipY    bool
+	flipX    bool
+	exposure float32
+}
+
+// Decode reads a Radiance RGBE (.hdr) image, flat or run length encoded
+func Decode(r io.Reader) (*Image, error) {
+	br := bufio.NewReader(r)
+	h, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	img := NewImage(image.Rect(0, 0, h.width, h.height))
+	scanline := make([]byte, h.width*4)
+	for row := 0; row < h.height; row++ {
+		if err := readScanline(br, scanline); err != nil {
+			return nil, err
+		}
+		y := row
+		if h.flipY {
+			y = h.height - 1 - row
+		}
+		for x := 0; x < h.width; x++ {
+			px := x
+			if h.flipX {
+				px = h.width - 1 - x
+			}
+			rc, gc, bc := rgbeToFloat(scanline[x*4:x*4+4], h.exposure)
+			img.SetRGBA(px, y, rc, gc, bc, 1)
+		}
+	}
+	return img, nil
+}
+
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	h, err := readHeader(bufio.NewReader(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: (&Image{}).ColorModel(), Width: h.width, Height: h.height}, nil
+}
+
+func readHeader(br *bufio.Reader) (header, error) {
+	h := header{exposure: 1}
+
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return h, err
+	}
+	if !strings.HasPrefix(line, "#?") {
+		return h, errNotRadiance
+	}
+
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil {
+			return h, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return h, fmt.Errorf("hdr: unsupported %s", line)
+		}
+		if strings.HasPrefix(line, "EXPOSURE=") {
+			var e float32
+			if _, err := fmt.Sscanf(line, "EXPOSURE=%g", &e); err == nil && e != 0 {
+				h.exposure *= e
+			}
+		}
+	}
+
+	line, err = br.ReadString('\n')
+	if err != nil {
+		return h, err
+	}
+	var ySign, xSign string
+	if _, err := fmt.Sscanf(line, "%1sY %d %1sX %d", &ySign, &h.height, &xSign, &h.width); err != nil {
+		return h, fmt.Errorf("hdr: unsupported resolution line %q", strings.TrimSpace(line))
+	}
+	// -Y is the usual top to bottom order, +Y stores the bottom row first
+	h.flipY = ySign == "+"
+	h.flipX = xSign == "-"
+	// divide rather than multiply so a huge size cannot overflow the check
+	if h.width <= 0 || h.height <= 0 || h.width > maxPixels/h.height {
+		return h, fmt.Errorf("hdr: invalid size %dx%d", h.width, h.height)
+	}
+	return h, nil
+}
+
+func readScanline(br *bufio.Reader, dst []byte) error {
+	width := len(dst) / 4
+	start := make([]byte, 4)
+	if _, err := io.ReadFull(br, start); err != nil {
+		return err
+	}
+
+	// new style RLE: 2 2 followed by the width, then each channel encoded separately
+	if width < 8 || width > 0x7fff || start[0] != 2 || start[1] != 2 || start[2]&0x80 != 0 {
+		copy(dst, start)
+		return readFlat(br, dst, 1)
+	}
+	if int(start[2])<<8|int(start[3]) != width {
+		return errBadScanline
+	}
+
+	for c := 0; c < 4; c++ {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return err
+			}
+			if count > 128 {
+				n := int(count) - 128
+				v, err := br.ReadByte()
+				if err != nil {
+					return err
+				}
+				if x+n > width {
+					return errBadScanline
+				}
+				for ; n > 0; n-- {
+					dst[x*4+c] = v
+					x++
+				}
+				continue
+			}
+			n := int(count)
+			if n == 0 || x+n > width {
+				return errBadScanline
+			}
+			for ; n > 0; n-- {
+				v, err := br.ReadByte()
+				if err != nil {
+					return err
+				}
+				dst[x*4+c] = v
+				x++
+			}
+		}
+	}
+	return nil
+}
+
+// readFlat reads uncompressed pixels, expanding old style 1 1 1 n repeat runs
+func readFlat(br *bufio.Reader, dst []byte, from int) error {
+	width := len(dst) / 4
+	shift := uint(0)
+	for x := from; x < width; {
+		px := dst[x*4 : x*4+4]
+		if _, err := io.ReadFull(br, px); err != nil {
+			return err
+		}
+		if px[0] == 1 && px[1] == 1 && px[2] == 1 {
+			if x == 0 {
+				return errBadScanline
+			}
+			n := int(px[3]) << shift
+			if x+n > width {
+				return errBadScanline
+			}
+			for ; n > 0; n-- {
+				copy(dst[x*4:x*4+4], dst[(x-1)*4:x*4])
+				x++
+			}
+			shift += 8
+			continue
+		}
+		shift = 0
+		x++
+	}
+	return nil
+}
+
+func rgbeToFloat(p []byte, exposure float32) (float32, float32, float32) {
+	if p[3] == 0 {
+		return 0, 0, 0
+	}
+	f := float32(math.Ldexp(1, int(p[3])-(128+8))) / exposure
+	return (float32(p[0]) + 0.5) * f, (float32(p[1]) + 0.5) * f, (float32(p[2]) + 0.5) * f
+}
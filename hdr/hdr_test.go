@@ -0,0 +1,183 @@
+package hdr
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testWidth, testHeight = 8, 2
+
+// testPixel is the RGBE value stored for x, y in the top to bottom image
+func testPixel(x, y int) [4]byte {
+	return [4]byte{byte(x * 10), byte(y * 20), 100, byte(128 + x%2)}
+}
+
+// radianceHeader builds a header; each extra line must end in a newline
+func radianceHeader(extra, resolution string) string {
+	return "#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n" + extra + "\n" + resolution + "\n"
+}
+
+func flat(pixel func(x, y int) [4]byte) []byte {
+	var b []byte
+	for y := 0; y < testHeight; y++ {
+		for x := 0; x < testWidth; x++ {
+			p := pixel(x, y)
+			b = append(b, p[:]...)
+		}
+	}
+	return b
+}
+
+// rle writes each scanline in the new style, with the constant blue channel
+// as a single run and the rest as literals
+func rle(pixel func(x, y int) [4]byte) []byte {
+	var b []byte
+	for y := 0; y < testHeight; y++ {
+		b = append(b, 2, 2, 0, testWidth)
+		for c := 0; c < 4; c++ {
+			if c == 2 {
+				b = append(b, 128+testWidth, pixel(0, y)[c])
+				continue
+			}
+			b = append(b, testWidth)
+			for x := 0; x < testWidth; x++ {
+				b = append(b, pixel(x, y)[c])
+			}
+		}
+	}
+	return b
+}
+
+func expected(pixel func(x, y int) [4]byte, exposure float64) *Image {
+	img := NewImage(image.Rect(0, 0, testWidth, testHeight))
+	for y := 0; y < testHeight; y++ {
+		for x := 0; x < testWidth; x++ {
+			p := pixel(x, y)
+			f := math.Ldexp(1, int(p[3])-136) / exposure
+			img.SetRGBA(x, y, float32((float64(p[0])+0.5)*f), float32((float64(p[1])+0.5)*f), float32((float64(p[2])+0.5)*f), 1)
+		}
+	}
+	return img
+}
+
+func TestDecode(t *testing.T) {
+	flipY := func(x, y int) [4]byte { return testPixel(x, testHeight-1-y) }
+	flipX := func(x, y int) [4]byte { return testPixel(testWidth-1-x, y) }
+	tests := []struct {
+		name     string
+		data     string
+		exposure float64
+	}{
+		{"flat", radianceHeader("", "-Y 2 +X 8") + string(flat(testPixel)), 1},
+		{"rle", radianceHeader("", "-Y 2 +X 8") + string(rle(testPixel)), 1},
+		{"bottom up", radianceHeader("", "+Y 2 +X 8") + string(rle(flipY)), 1},
+		{"right to left", radianceHeader("", "-Y 2 -X 8") + string(flat(flipX)), 1},
+		{"exposure", radianceHeader("EXPOSURE=2\nEXPOSURE=0.5\nEXPOSURE=4\n", "-Y 2 +X 8") + string(rle(testPixel)), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Decode(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := expected(testPixel, tt.exposure)
+			if img.Rect != want.Rect {
+				t.Fatalf("bounds %v, want %v", img.Rect, want.Rect)
+			}
+			for i := range want.Pix {
+				if img.Pix[i] != want.Pix[i] {
+					t.Fatalf("pixel %d channel %d = %v, want %v", i/4, i%4, img.Pix[i], want.Pix[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeOldStyleRuns(t *testing.T) {
+	// a single pixel followed by a 1 1 1 n repeat for the rest of each row
+	var data []byte
+	for y := 0; y < testHeight; y++ {
+		data = append(data, 64, 32, 16, 130, 1, 1, 1, testWidth-1)
+	}
+	img, err := Decode(strings.NewReader(radianceHeader("", "-Y 2 +X 8") + string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := expected(func(x, y int) [4]byte { return [4]byte{64, 32, 16, 130} }, 1)
+	for i := range want.Pix {
+		if img.Pix[i] != want.Pix[i] {
+			t.Fatalf("pixel %d channel %d = %v, want %v", i/4, i%4, img.Pix[i], want.Pix[i])
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing magic", "P6\n"},
+		{"unsupported format", "#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 2 +X 8\n"},
+		{"bad resolution", radianceHeader("", "Y 2 X 8")},
+		{"zero size", radianceHeader("", "-Y 0 +X 8")},
+		{"negative size", radianceHeader("", "-Y 2 +X -8")},
+		{"huge size", radianceHeader("", "-Y 3000000000 +X 3000000000")},
+		{"too many pixels", radianceHeader("", "-Y 16384 +X 16384")},
+		{"truncated pixels", radianceHeader("", "-Y 2 +X 8") + string(flat(testPixel)[:40])},
+		{"rle width mismatch", radianceHeader("", "-Y 2 +X 8") + "\x02\x02\x00\x09"},
+		{"rle run past the row", radianceHeader("", "-Y 2 +X 8") + "\x02\x02\x00\x08\x89\x00"},
+		{"rle zero count", radianceHeader("", "-Y 2 +X 8") + "\x02\x02\x00\x08\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(strings.NewReader(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDecodeConfigSize(t *testing.T) {
+	if _, err := DecodeConfig(strings.NewReader(radianceHeader("", "-Y 3000000000 +X 3000000000"))); err == nil {
+		t.Error("DecodeConfig accepted an oversized image")
+	}
+	cfg, err := DecodeConfig(strings.NewReader(radianceHeader("", "-Y 8192 +X 16384")))
+	if err != nil || cfg.Width != 16384 || cfg.Height != 8192 {
+		t.Errorf("got %dx%d, %v, want the largest allowed size", cfg.Width, cfg.Height, err)
+	}
+}
+
+func TestRegisteredFormat(t *testing.T) {
+	data := radianceHeader("", "-Y 2 +X 8") + string(rle(testPixel))
+
+	cfg, format, err := image.DecodeConfig(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "hdr" || cfg.Width != testWidth || cfg.Height != testHeight {
+		t.Errorf("got %s %dx%d, want hdr %dx%d", format, cfg.Width, cfg.Height, testWidth, testHeight)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader([]byte(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := img.(*Image); !ok {
+		t.Errorf("image.Decode returned %T, want *hdr.Image", img)
+	}
+}
+
+func TestAtClamps(t *testing.T) {
+	img := NewImage(image.Rect(0, 0, 1, 1))
+	img.SetRGBA(0, 0, 2, 0.5, -1, 1)
+	r, g, b, a := img.At(0, 0).RGBA()
+	if r != 0xffff || g != 0x8000 || b != 0 || a != 0xffff {
+		t.Errorf("At = %x %x %x %x, want ffff 8000 0 ffff", r, g, b, a)
+	}
+	if _, _, _, a := img.At(1, 0).RGBA(); a != 0 {
+		t.Errorf("At outside the bounds has alpha %x, want 0", a)
+	}
+}
@@ -0,0 +1,68 @@
+package hdr
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Image holds unclamped linear RGBA pixels as float32, four per pixel
+type Image struct {
+	Pix    []float32
+	Stride int
+	Rect   image.Rectangle
+}
+
+func NewImage(r image.Rectangle) *Image {
+	return &Image{
+		Pix:    make([]float32, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *Image) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (p *Image) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+// At clamps the pixel to [0, 1] so the image can be used wherever an
+// image.Image is expected, such as previews and PNG export
+func (p *Image) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA64{}
+	}
+	r, g, b, a := p.RGBAAt(x, y)
+	return color.RGBA64{R: to16(r * a), G: to16(g * a), B: to16(b * a), A: to16(a)}
+}
+
+func (p *Image) RGBAAt(x, y int) (float32, float32, float32, float32) {
+	i := p.PixOffset(x, y)
+	return p.Pix[i], p.Pix[i+1], p.Pix[i+2], p.Pix[i+3]
+}
+
+func (p *Image) SetRGBA(x, y int, r, g, b, a float32) {
+	i := p.PixOffset(x, y)
+	p.Pix[i], p.Pix[i+1], p.Pix[i+2], p.Pix[i+3] = r, g, b, a
+}
+
+func (p *Image) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+func to16(v float32) uint16 {
+	return uint16(math.Round(float64(clamp01(v)) * 0xffff))
+}
+
+func clamp01(v float32) float32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,158 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kevholditch/opengl-playground/atlas"
+)
+
+type AnimationMode int
+
+const (
+	AnimationLoop AnimationMode = iota
+	AnimationPingPong
+	AnimationOnce
+)
+
+var errNoFrames = errors.New("animation has no frames")
+
+var errDurationCount = errors.New("animation needs one duration per frame")
+
+var errDuration = errors.New("animation frame durations must be positive")
+
+// Animation is a sequence of sprite sheet frames, each shown for its own
+// duration in seconds
+type Animation struct {
+	Frames    []int
+	Durations []float32
+	Mode      AnimationMode
+}
+
+// NewAnimation builds an animation that shows every frame for the same duration
+func NewAnimation(frames []int, frameDuration float32, mode AnimationMode) (*Animation, error) {
+	durations := make([]float32, len(frames))
+	for i := range durations {
+		durations[i] = frameDuration
+	}
+	return NewAnimationWithDurations(frames, durations, mode)
+}
+
+// NewAnimationWithDurations builds an animation that shows frames[i] for durations[i]
+func NewAnimationWithDurations(frames []int, durations []float32, mode AnimationMode) (*Animation, error) {
+	if len(frames) == 0 {
+		return nil, errNoFrames
+	}
+	if len(durations) != len(frames) {
+		return nil, errDurationCount
+	}
+	for i, f := range frames {
+		if f < 0 {
+			return nil, fmt.Errorf("animation frame %d is negative (%d)", i, f)
+		}
+		if durations[i] <= 0 {
+			return nil, errDuration
+		}
+	}
+	return &Animation{Frames: frames, Durations: durations, Mode: mode}, nil
+}
+
+// checkFrames makes sure every frame exists in the sheet, so Region cannot
+// index past the end of it
+func (a *Animation) checkFrames(sheet *SpriteSheet) error {
+	for i, f := range a.Frames {
+		if f < 0 || f >= sheet.FrameCount() {
+			return fmt.Errorf("animation frame %d is %d but the sprite sheet has %d frames", i, f, sheet.FrameCount())
+		}
+	}
+	return nil
+}
+
+// AnimationPlayer plays an animation from a sprite sheet
+type AnimationPlayer struct {
+	sheet     *SpriteSheet
+	anim      *Animation
+	index     int
+	direction int
+	elapsed   float32
+	finished  bool
+}
+
+func NewAnimationPlayer(sheet *SpriteSheet, anim *Animation) (*AnimationPlayer, error) {
+	if err := anim.checkFrames(sheet); err != nil {
+		return nil, err
+	}
+	return &AnimationPlayer{sheet: sheet, anim: anim, direction: 1}, nil
+}
+
+// Play switches to a different animation and starts it from its first frame.
+// The current animation keeps playing if anim uses frames the sheet lacks.
+func (p *AnimationPlayer) Play(anim *Animation) error {
+	if err := anim.checkFrames(p.sheet); err != nil {
+		return err
+	}
+	p.anim = anim
+	p.Reset()
+	return nil
+}
+
+func (p *AnimationPlayer) Reset() {
+	p.index = 0
+	p.direction = 1
+	p.elapsed = 0
+	p.finished = false
+}
+
+// Update advances the animation by dt seconds and returns the frame to draw
+func (p *AnimationPlayer) Update(dt float32) atlas.Region {
+	p.elapsed += dt
+	for !p.finished {
+		d := p.anim.Durations[p.index]
+		// Durations is exported, so guard against a zero edited in after construction
+		if d <= 0 || p.elapsed < d {
+			break
+		}
+		p.elapsed -= d
+		p.step()
+	}
+	return p.Region()
+}
+
+func (p *AnimationPlayer) step() {
+	last := len(p.anim.Frames) - 1
+
+	switch p.anim.Mode {
+	case AnimationLoop:
+		p.index = (p.index + 1) % len(p.anim.Frames)
+	case AnimationPingPong:
+		if last == 0 {
+			return
+		}
+		if p.index+p.direction < 0 || p.index+p.direction > last {
+			p.direction = -p.direction
+		}
+		p.index += p.direction
+	case AnimationOnce:
+		if p.index == last {
+			p.finished = true
+			p.elapsed = 0
+			return
+		}
+		p.index++
+	}
+}
+
+// Frame returns the sprite sheet frame currently showing
+func (p *AnimationPlayer) Frame() int {
+	return p.anim.Frames[p.index]
+}
+
+// Region returns the UV rectangle of the frame currently showing
+func (p *AnimationPlayer) Region() atlas.Region {
+	return p.sheet.Frame(p.Frame())
+}
+
+// Finished reports whether a one shot animation has reached its last frame
+func (p *AnimationPlayer) Finished() bool {
+	return p.finished
+}
@@ -0,0 +1,127 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/kevholditch/opengl-playground/atlas"
+)
+
+func TestNewAnimationWithDurations(t *testing.T) {
+	tests := []struct {
+		name      string
+		frames    []int
+		durations []float32
+		ok        bool
+	}{
+		{"valid", []int{0, 1, 2}, []float32{0.1, 0.2, 0.1}, true},
+		{"no frames", nil, nil, false},
+		{"missing duration", []int{0, 1}, []float32{0.1}, false},
+		{"zero duration", []int{0, 1}, []float32{0.1, 0}, false},
+		{"negative duration", []int{0}, []float32{-1}, false},
+		{"negative frame", []int{0, -1}, []float32{0.1, 0.1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAnimationWithDurations(tt.frames, tt.durations, AnimationLoop)
+			if (err == nil) != tt.ok {
+				t.Errorf("got error %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAnimationPlayerFrameRange(t *testing.T) {
+	sheet := &SpriteSheet{frames: make([]atlas.Region, 3)}
+	inRange, _ := NewAnimation([]int{0, 2}, 0.1, AnimationLoop)
+	outOfRange, _ := NewAnimation([]int{1, 3}, 0.1, AnimationLoop)
+
+	if _, err := NewAnimationPlayer(sheet, outOfRange); err == nil {
+		t.Error("NewAnimationPlayer accepted a frame past the end of the sheet")
+	}
+	p, err := NewAnimationPlayer(sheet, inRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Play(outOfRange); err == nil {
+		t.Error("Play accepted a frame past the end of the sheet")
+	}
+	p.Update(0.15)
+	if p.Frame() != 2 {
+		t.Errorf("frame %d after a rejected Play, want the original animation's frame 2", p.Frame())
+	}
+}
+
+func TestAnimationPlayback(t *testing.T) {
+	tests := []struct {
+		name      string
+		frames    []int
+		durations []float32
+		mode      AnimationMode
+		dt        float32
+		want      []int
+		finished  []bool
+	}{
+		{
+			"loop", []int{10, 11, 12}, []float32{0.25, 0.25, 0.25}, AnimationLoop, 0.25,
+			[]int{11, 12, 10, 11, 12, 10},
+			[]bool{false, false, false, false, false, false},
+		},
+		{
+			"ping pong", []int{10, 11, 12}, []float32{0.25, 0.25, 0.25}, AnimationPingPong, 0.25,
+			[]int{11, 12, 11, 10, 11, 12},
+			[]bool{false, false, false, false, false, false},
+		},
+		{
+			"ping pong single frame", []int{7}, []float32{0.25}, AnimationPingPong, 0.25,
+			[]int{7, 7, 7},
+			[]bool{false, false, false},
+		},
+		{
+			"once", []int{10, 11, 12}, []float32{0.25, 0.25, 0.25}, AnimationOnce, 0.25,
+			[]int{11, 12, 12, 12},
+			[]bool{false, false, true, true},
+		},
+		{
+			"half steps", []int{10, 11}, []float32{0.25, 0.25}, AnimationLoop, 0.125,
+			[]int{10, 11, 11, 10, 10, 11},
+			[]bool{false, false, false, false, false, false},
+		},
+		{
+			"large step skips frames", []int{10, 11, 12}, []float32{0.25, 0.25, 0.25}, AnimationLoop, 0.5,
+			[]int{12, 11, 10},
+			[]bool{false, false, false},
+		},
+		{
+			"per frame durations", []int{10, 11, 12}, []float32{0.5, 0.25, 0.25}, AnimationLoop, 0.25,
+			[]int{10, 11, 12, 10, 10, 11},
+			[]bool{false, false, false, false, false, false},
+		},
+	}
+	sheet := &SpriteSheet{frames: make([]atlas.Region, 13)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anim, err := NewAnimationWithDurations(tt.frames, tt.durations, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := NewAnimationPlayer(sheet, anim)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Frame() != tt.frames[0] || p.Finished() {
+				t.Fatalf("starts on frame %d finished %v, want %d", p.Frame(), p.Finished(), tt.frames[0])
+			}
+			for i := range tt.want {
+				p.Update(tt.dt)
+				if p.Frame() != tt.want[i] || p.Finished() != tt.finished[i] {
+					t.Fatalf("step %d: frame %d finished %v, want %d %v", i+1, p.Frame(), p.Finished(), tt.want[i], tt.finished[i])
+				}
+			}
+
+			p.Reset()
+			if p.Frame() != tt.frames[0] || p.Finished() {
+				t.Errorf("after Reset on frame %d finished %v, want %d", p.Frame(), p.Finished(), tt.frames[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package render
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// OrthoCamera is a 2D projection with the origin in the bottom left and one
+// unit per screen coordinate. It follows the window as it is resized.
+type OrthoCamera struct {
+	Near, Far     float32
+	width, height float32
+	projection    mgl32.Mat4
+}
+
+func NewOrthoCamera(w *Window) *OrthoCamera {
+	c := &OrthoCamera{Near: -1, Far: 1}
+	width, height := w.Size()
+	c.Resize(width, height)
+	w.OnResize(c.Resize)
+	return c
+}
+
+// Resize rebuilds the projection for a new viewport size
+func (c *OrthoCamera) Resize(width, height int) {
+	c.width, c.height = float32(width), float32(height)
+	c.projection = mgl32.Ortho(0, c.width, 0, c.height, c.Near, c.Far)
+}
+
+func (c *OrthoCamera) Projection() mgl32.Mat4 {
+	return c.projection
+}
+
+func (c *OrthoCamera) Size() (float32, float32) {
+	return c.width, c.height
+}
+
+// PerspectiveCamera is a 3D projection whose aspect ratio follows the window
+type PerspectiveCamera struct {
+	FovY       float32 // in degrees
+	Near, Far  float32
+	aspect     float32
+	projection mgl32.Mat4
+}
+
+func NewPerspectiveCamera(w *Window, fovY, near, far float32) *PerspectiveCamera {
+	c := &PerspectiveCamera{FovY: fovY, Near: near, Far: far}
+	width, height := w.Size()
+	c.Resize(width, height)
+	w.OnResize(c.Resize)
+	return c
+}
+
+// Resize rebuilds the projection for a new viewport size
+func (c *PerspectiveCamera) Resize(width, height int) {
+	if height == 0 {
+		return
+	}
+	c.aspect = float32(width) / float32(height)
+	c.projection = mgl32.Perspective(mgl32.DegToRad(c.FovY), c.aspect, c.Near, c.Far)
+}
+
+func (c *PerspectiveCamera) Projection() mgl32.Mat4 {
+	return c.projection
+}
+
+func (c *PerspectiveCamera) Aspect() float32 {
+	return c.aspect
+}
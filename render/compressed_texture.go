@@ -0,0 +1,148 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/kevholditch/opengl-playground/compressed"
+)
+
+type compressedFormat struct {
+	linear uint32
+	srgb   uint32
+
+	// usable from GL major.minor, or through extension on older contexts;
+	// a zero major means only the extension provides it
+	major, minor int
+	extension    string
+}
+
+const (
+	extS3TC = "GL_EXT_texture_compression_s3tc"
+	extRGTC = "GL_ARB_texture_compression_rgtc"
+	extBPTC = "GL_ARB_texture_compression_bptc"
+	extETC2 = "GL_ARB_ES3_compatibility"
+)
+
+var compressedFormats = map[compressed.Format]compressedFormat{
+	compressed.FormatBC1:          {gl.COMPRESSED_RGBA_S3TC_DXT1_EXT, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT, 0, 0, extS3TC},
+	compressed.FormatBC2:          {gl.COMPRESSED_RGBA_S3TC_DXT3_EXT, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT, 0, 0, extS3TC},
+	compressed.FormatBC3:          {gl.COMPRESSED_RGBA_S3TC_DXT5_EXT, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT, 0, 0, extS3TC},
+	compressed.FormatBC4:          {gl.COMPRESSED_RED_RGTC1, gl.COMPRESSED_RED_RGTC1, 3, 0, extRGTC},
+	compressed.FormatBC5:          {gl.COMPRESSED_RG_RGTC2, gl.COMPRESSED_RG_RGTC2, 3, 0, extRGTC},
+	compressed.FormatBC6HUnsigned: {gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB, gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB, 4, 2, extBPTC},
+	compressed.FormatBC6HSigned:   {gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB, gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB, 4, 2, extBPTC},
+	compressed.FormatBC7:          {gl.COMPRESSED_RGBA_BPTC_UNORM_ARB, gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB, 4, 2, extBPTC},
+	compressed.FormatETC2RGB:      {gl.COMPRESSED_RGB8_ETC2, gl.COMPRESSED_SRGB8_ETC2, 4, 3, extETC2},
+	compressed.FormatETC2RGBA1:    {gl.COMPRESSED_RGB8_PUNCHTHROUGH_ALPHA1_ETC2, gl.COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2, 4, 3, extETC2},
+	compressed.FormatETC2RGBA:     {gl.COMPRESSED_RGBA8_ETC2_EAC, gl.COMPRESSED_SRGB8_ALPHA8_ETC2_EAC, 4, 3, extETC2},
+}
+
+// supported reports whether the current context takes the format natively. It
+// goes by GL version and extensions rather than COMPRESSED_TEXTURE_FORMATS,
+// which drivers may leave S3TC and BPTC out of, and is not cached since each
+// window's context can differ.
+func (cf compressedFormat) supported(srgb bool) bool {
+	if !(cf.major > 0 && glVersionAtLeast(cf.major, cf.minor)) && !glfw.ExtensionSupported(cf.extension) {
+		return false
+	}
+	// the sRGB S3TC formats come from a separate extension
+	if srgb && cf.extension == extS3TC {
+		return glfw.ExtensionSupported("GL_EXT_texture_sRGB") || glfw.ExtensionSupported("GL_EXT_texture_compression_s3tc_srgb")
+	}
+	return true
+}
+
+func isCompressedFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".ktx", ".ktx2", ".dds":
+		return true
+	}
+	return false
+}
+
+func NewCompressedTextureFromFile(file string, opts TextureOptions) (*Texture, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := compressed.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewCompressedTexture(t, opts)
+}
+
+// NewCompressedTexture uploads every mip level of a KTX or DDS texture as is
+// when the driver supports its format, otherwise BC1 and BC3 are decoded in
+// software and uploaded as RGBA. Whether the data is sRGB comes from the file.
+func NewCompressedTexture(t *compressed.Texture, opts TextureOptions) (*Texture, error) {
+	if len(t.Levels) == 0 {
+		return nil, fmt.Errorf("%v texture has no mip levels", t.Format)
+	}
+
+	cf, known := compressedFormats[t.Format]
+	internalFmt := cf.linear
+	if t.SRGB {
+		internalFmt = cf.srgb
+	}
+	native := known && cf.supported(t.SRGB)
+	if !native && t.Format != compressed.FormatRGBA8 && t.Format != compressed.FormatBC1 && t.Format != compressed.FormatBC3 {
+		return nil, fmt.Errorf("driver does not support %v textures and they cannot be decoded in software", t.Format)
+	}
+
+	// a file's own mip chain allows mipmap filters; otherwise only a lone level
+	// decoded in software can have mipmaps generated for it
+	hasMipLevels := len(t.Levels) > 1 || !native && opts.GenerateMipmaps
+	opts = opts.withMipLevels(hasMipLevels)
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+
+	texture := Texture{
+		handle: handle,
+		target: gl.TEXTURE_2D,
+		width:  int32(t.Width),
+		height: int32(t.Height),
+	}
+	texture.Bind(0)
+
+	opts.apply(texture.target)
+
+	rgbaFmt := int32(gl.RGBA8)
+	if t.SRGB {
+		rgbaFmt = gl.SRGB8_ALPHA8
+	}
+
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, level := range t.Levels {
+		if native {
+			gl.CompressedTexImage2D(texture.target, int32(i), internalFmt, int32(level.Width), int32(level.Height), 0,
+				int32(len(level.Data)), gl.Ptr(level.Data))
+			continue
+		}
+		rgba, err := level.Decode(t.Format)
+		if err != nil {
+			gl.DeleteTextures(1, &handle)
+			return nil, err
+		}
+		gl.TexImage2D(texture.target, int32(i), rgbaFmt, int32(level.Width), int32(level.Height), 0,
+			gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+
+	// the file's own mip chain is used as is; only a lone uncompressed level can be extended
+	gl.TexParameteri(texture.target, gl.TEXTURE_MAX_LEVEL, int32(len(t.Levels)-1))
+	if len(t.Levels) == 1 && !native {
+		gl.TexParameteri(texture.target, gl.TEXTURE_MAX_LEVEL, 1000)
+		opts.generateMipmaps(texture.target)
+	}
+
+	return &texture, nil
+}
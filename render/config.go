@@ -0,0 +1,172 @@
+package render
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config describes the window to open. The zero value of each setting asks
+// for GLFW's default, so a config file only needs the settings it changes.
+type Config struct {
+	MajorVersion int    `json:"majorVersion"`
+	MinorVersion int    `json:"minorVersion"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Title        string `json:"title"`
+	SwapInterval int    `json:"swapInterval"`
+	Resizable    bool   `json:"resizable"`
+	// ScaleToMonitor sizes the window by the monitor's content scale so it
+	// appears the same physical size on HiDPI displays
+	ScaleToMonitor bool `json:"scaleToMonitor"`
+
+	// Fullscreen opens the window exclusive fullscreen on Monitor, an index
+	// into Monitors(). Width and Height pick the video mode, or zero keeps the desktop's.
+	Fullscreen bool `json:"fullscreen"`
+	// Borderless opens an undecorated window covering Monitor at the desktop
+	// video mode, so the display never changes mode. It wins over Fullscreen.
+	Borderless  bool `json:"borderless"`
+	Monitor     int  `json:"monitor"`
+	RefreshRate int  `json:"refreshRate"`
+
+	// Samples is the MSAA sample count, 0 for no multisampling
+	Samples int `json:"samples"`
+	// Debug asks for a debug context, which reports more driver errors
+	Debug bool `json:"debug"`
+	// DepthBits and StencilBits default to 24 and 8 when zero
+	DepthBits   int `json:"depthBits"`
+	StencilBits int `json:"stencilBits"`
+	// SRGB makes the default framebuffer convert linear colour to sRGB on write
+	SRGB bool `json:"srgb"`
+
+	Undecorated bool `json:"undecorated"`
+	// Floating keeps the window above other windows
+	Floating bool `json:"floating"`
+	// Transparent lets the desktop show through where the framebuffer alpha is below 1
+	Transparent bool `json:"transparent"`
+	// Position places the window's top left corner on the desktop, nil lets the OS choose
+	Position *WindowPos `json:"position"`
+
+	Icon image.Image `json:"-"`
+	// IconFile is loaded into Icon by NewWindow when Icon is nil
+	IconFile string  `json:"icon"`
+	Cursor   *Cursor `json:"-"`
+
+	// Share makes the new window's context share textures, buffers and
+	// shader programs with another window
+	Share *Window `json:"-"`
+}
+
+type WindowPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Load reads a config from a .json or .toml file over the top of cfg,
+// so settings missing from the file keep their current values
+func (cfg *Config) Load(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+	case ".toml":
+		values, err := parseTOML(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		// the TOML keys match the JSON ones, so let encoding/json fill the struct
+		if data, err = json.Marshal(values); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+	default:
+		return fmt.Errorf("%s: config files must be .json or .toml", file)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+// LoadConfig reads a config file, see Config.Load
+func LoadConfig(file string) (Config, error) {
+	var cfg Config
+	err := cfg.Load(file)
+	return cfg, err
+}
+
+// ParseFlags adds a flag for each setting to fs, defaulting to the current
+// values, then parses args. A -config file is loaded first and any flags
+// given on the command line override it.
+func (cfg *Config) ParseFlags(fs *flag.FlagSet, args []string) error {
+	file := fs.String("config", "", "load window settings from a .json or .toml file")
+	fs.StringVar(&cfg.Title, "title", cfg.Title, "window title")
+	fs.IntVar(&cfg.Width, "width", cfg.Width, "window width")
+	fs.IntVar(&cfg.Height, "height", cfg.Height, "window height")
+	fs.IntVar(&cfg.MajorVersion, "gl-major", cfg.MajorVersion, "OpenGL major version")
+	fs.IntVar(&cfg.MinorVersion, "gl-minor", cfg.MinorVersion, "OpenGL minor version")
+	fs.IntVar(&cfg.SwapInterval, "swap-interval", cfg.SwapInterval, "frames to wait before swapping, 0 disables vsync")
+	fs.BoolVar(&cfg.Resizable, "resizable", cfg.Resizable, "allow the window to be resized")
+	fs.BoolVar(&cfg.ScaleToMonitor, "scale-to-monitor", cfg.ScaleToMonitor, "scale the window by the monitor's content scale")
+	fs.BoolVar(&cfg.Fullscreen, "fullscreen", cfg.Fullscreen, "open exclusive fullscreen")
+	fs.BoolVar(&cfg.Borderless, "borderless", cfg.Borderless, "cover the monitor with an undecorated window")
+	fs.IntVar(&cfg.Monitor, "monitor", cfg.Monitor, "monitor to go fullscreen on")
+	fs.IntVar(&cfg.RefreshRate, "refresh-rate", cfg.RefreshRate, "fullscreen refresh rate, 0 for the current rate")
+	fs.IntVar(&cfg.Samples, "samples", cfg.Samples, "MSAA samples")
+	fs.BoolVar(&cfg.Debug, "debug", cfg.Debug, "create a debug OpenGL context")
+	fs.IntVar(&cfg.DepthBits, "depth-bits", cfg.DepthBits, "depth buffer bits, 0 for the default")
+	fs.IntVar(&cfg.StencilBits, "stencil-bits", cfg.StencilBits, "stencil buffer bits, 0 for the default")
+	fs.BoolVar(&cfg.SRGB, "srgb", cfg.SRGB, "use an sRGB framebuffer")
+	fs.BoolVar(&cfg.Undecorated, "undecorated", cfg.Undecorated, "open without a border or title bar")
+	fs.BoolVar(&cfg.Floating, "floating", cfg.Floating, "keep the window on top")
+	fs.BoolVar(&cfg.Transparent, "transparent", cfg.Transparent, "use a transparent framebuffer")
+	fs.Var(positionFlag{cfg}, "pos", "window position as x,y")
+	fs.StringVar(&cfg.IconFile, "icon", cfg.IconFile, "window icon image")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return nil
+	}
+	if err := cfg.Load(*file); err != nil {
+		return err
+	}
+	return fs.Parse(args)
+}
+
+type positionFlag struct {
+	cfg *Config
+}
+
+func (p positionFlag) String() string {
+	if p.cfg == nil || p.cfg.Position == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d,%d", p.cfg.Position.X, p.cfg.Position.Y)
+}
+
+func (p positionFlag) Set(value string) error {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("position %q should be x,y", value)
+	}
+	x, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return err
+	}
+	y, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return err
+	}
+	p.cfg.Position = &WindowPos{X: x, Y: y}
+	return nil
+}
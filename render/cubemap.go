@@ -0,0 +1,148 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+// Cube map faces in the order OpenGL numbers them from TEXTURE_CUBE_MAP_POSITIVE_X
+const (
+	CubeFacePositiveX = iota
+	CubeFaceNegativeX
+	CubeFacePositiveY
+	CubeFaceNegativeY
+	CubeFacePositiveZ
+	CubeFaceNegativeZ
+)
+
+type CubeMap struct {
+	*Texture
+}
+
+// NewCubeMapFromFiles loads one image per face, in CubeFace order
+func NewCubeMapFromFiles(files [6]string, opts TextureOptions) (*CubeMap, error) {
+	var faces [6]image.Image
+	for i, file := range files {
+		img, err := loadImageFile(file)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = img
+	}
+	return NewCubeMap(faces, opts)
+}
+
+// NewCubeMapFromFile loads a single image laid out as a cross or a strip
+func NewCubeMapFromFile(file string, opts TextureOptions) (*CubeMap, error) {
+	img, err := loadImageFile(file)
+	if err != nil {
+		return nil, err
+	}
+	faces, err := SplitCubeMapImage(img)
+	if err != nil {
+		return nil, err
+	}
+	return NewCubeMap(faces, opts)
+}
+
+// NewCubeMap uploads six square faces of the same size, in CubeFace order, and
+// turns on seamless filtering across face edges where the context supports it
+func NewCubeMap(faces [6]image.Image, opts TextureOptions) (*CubeMap, error) {
+	opts = opts.withDefaults()
+
+	// check and convert every face before creating anything so a bad face
+	// doesn't leave a half built texture bound
+	size := faces[0].Bounds().Size()
+	if size.X != size.Y {
+		return nil, fmt.Errorf("cube map faces must be square, got %dx%d", size.X, size.Y)
+	}
+	var pixels [6]*image.RGBA
+	for i, face := range faces {
+		if face.Bounds().Size() != size {
+			return nil, fmt.Errorf("cube map face %d is %v, expected %v", i, face.Bounds().Size(), size)
+		}
+		rgba, err := toRGBA(face)
+		if err != nil {
+			return nil, err
+		}
+		pixels[i] = rgba
+	}
+
+	UseSeamlessCubeMaps()
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+
+	texture := Texture{
+		handle: handle,
+		target: gl.TEXTURE_CUBE_MAP,
+		width:  int32(size.X),
+		height: int32(size.Y),
+	}
+	texture.Bind(0)
+	opts.apply(texture.target)
+
+	for i, rgba := range pixels {
+		gl.TexImage2D(uint32(gl.TEXTURE_CUBE_MAP_POSITIVE_X+i), 0, opts.internalFormat(), texture.width, texture.height, 0,
+			gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	}
+
+	opts.generateMipmaps(texture.target)
+
+	return &CubeMap{Texture: &texture}, nil
+}
+
+// SplitCubeMapImage cuts a single image into the six cube faces. It accepts a
+// horizontal cross (4x3 faces), a vertical cross (3x4 faces, with -Z upside down
+// at the bottom) or a strip of six faces in CubeFace order, either way round.
+func SplitCubeMapImage(img image.Image) ([6]image.Image, error) {
+	var faces [6]image.Image
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// face grid positions (column, row) for each layout, in CubeFace order
+	var cells [6]image.Point
+	var face int
+	switch {
+	case w*3 == h*4:
+		face = w / 4
+		cells = [6]image.Point{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {3, 1}}
+	case w*4 == h*3:
+		face = w / 3
+		cells = [6]image.Point{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {1, 3}}
+	case w == h*6:
+		face = h
+		cells = [6]image.Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}}
+	case h == w*6:
+		face = w
+		cells = [6]image.Point{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 5}}
+	default:
+		return faces, fmt.Errorf("cannot split a %dx%d image into cube faces", w, h)
+	}
+
+	for i, c := range cells {
+		src := image.Rect(0, 0, face, face).Add(b.Min).Add(c.Mul(face))
+		dst := image.NewRGBA(image.Rect(0, 0, face, face))
+		draw.Draw(dst, dst.Bounds(), img, src.Min, draw.Src)
+		faces[i] = dst
+	}
+
+	if w*4 == h*3 {
+		faces[CubeFaceNegativeZ] = rotate180(faces[CubeFaceNegativeZ].(*image.RGBA))
+	}
+	return faces, nil
+}
+
+func rotate180(img *image.RGBA) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			out.SetRGBA(b.Dx()-1-x, b.Dy()-1-y, img.RGBAAt(x, y))
+		}
+	}
+	return out
+}
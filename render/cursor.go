@@ -0,0 +1,61 @@
+package render
+
+import (
+	"image"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+type CursorShape int
+
+const (
+	CursorArrow     = CursorShape(glfw.ArrowCursor)
+	CursorIBeam     = CursorShape(glfw.IBeamCursor)
+	CursorCrosshair = CursorShape(glfw.CrosshairCursor)
+	CursorHand      = CursorShape(glfw.HandCursor)
+	CursorHResize   = CursorShape(glfw.HResizeCursor)
+	CursorVResize   = CursorShape(glfw.VResizeCursor)
+)
+
+// Cursor is a mouse cursor image that can be shown over any window
+type Cursor struct {
+	handle *glfw.Cursor
+}
+
+// NewCursor makes a cursor from an image, with the click point at hotX, hotY
+// from the top left
+func NewCursor(img image.Image, hotX, hotY int) *Cursor {
+	return &Cursor{handle: glfw.CreateCursor(img, hotX, hotY)}
+}
+
+func NewCursorFromFile(file string, hotX, hotY int) (*Cursor, error) {
+	img, err := loadImageFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewCursor(img, hotX, hotY), nil
+}
+
+// NewStandardCursor returns one of the system's own cursors
+func NewStandardCursor(shape CursorShape) *Cursor {
+	return &Cursor{handle: glfw.CreateStandardCursor(glfw.StandardCursor(shape))}
+}
+
+func (c *Cursor) Delete() {
+	c.handle.Destroy()
+}
+
+// SetCursor changes the cursor shown over the window, nil restores the arrow
+func (w *Window) SetCursor(c *Cursor) {
+	if c == nil {
+		w.handle.SetCursor(nil)
+		return
+	}
+	w.handle.SetCursor(c.handle)
+}
+
+// SetIcon sets the window icon. Several sizes can be given and the system
+// picks the closest, macOS ignores window icons.
+func (w *Window) SetIcon(images ...image.Image) {
+	w.handle.SetIcon(images)
+}
@@ -0,0 +1,219 @@
+package render
+
+import (
+	"log"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Event is one of the typed input and window events below
+type Event interface {
+	isEvent()
+}
+
+type KeyEvent struct {
+	Key    Key
+	Action KeyAction
+	Mods   Modifier
+}
+
+// CharEvent is a unicode character typed, after keyboard layout and dead keys
+type CharEvent struct {
+	Char rune
+}
+
+// MouseMoveEvent is the cursor position in window coordinates, from the top left
+type MouseMoveEvent struct {
+	X, Y float64
+}
+
+type MouseButtonEvent struct {
+	Button MouseButton
+	Action KeyAction
+	Mods   Modifier
+}
+
+type ScrollEvent struct {
+	X, Y float64
+}
+
+type CursorEnterEvent struct {
+	Entered bool
+}
+
+// ResizeEvent gives the window size in screen coordinates and the framebuffer size in pixels
+type ResizeEvent struct {
+	Width, Height                       int
+	FramebufferWidth, FramebufferHeight int
+}
+
+type FocusEvent struct {
+	Focused bool
+}
+
+// CloseEvent is sent when the user asks to close the window
+type CloseEvent struct{}
+
+type FileDropEvent struct {
+	Paths []string
+}
+
+func (KeyEvent) isEvent()         {}
+func (CharEvent) isEvent()        {}
+func (MouseMoveEvent) isEvent()   {}
+func (MouseButtonEvent) isEvent() {}
+func (ScrollEvent) isEvent()      {}
+func (CursorEnterEvent) isEvent() {}
+func (ResizeEvent) isEvent()      {}
+func (FocusEvent) isEvent()       {}
+func (CloseEvent) isEvent()       {}
+func (FileDropEvent) isEvent()    {}
+
+const defaultEventCapacity = 256
+
+// EventQueue buffers events until they are read. It needs no window so tests
+// can fill one with synthetic events. When it is full the oldest mouse move is
+// dropped to make room, since a later move supersedes it, and only if there
+// are none is the oldest event of any kind dropped.
+type EventQueue struct {
+	events   []Event
+	capacity int
+	dropped  int
+}
+
+func NewEventQueue(capacity int) *EventQueue {
+	return &EventQueue{capacity: capacity}
+}
+
+// Push adds an event, reporting false if an older event had to be dropped
+func (q *EventQueue) Push(e Event) bool {
+	room := true
+	if q.capacity > 0 && len(q.events) >= q.capacity {
+		room = false
+		q.dropped++
+		drop := 0
+		for i, queued := range q.events {
+			if _, ok := queued.(MouseMoveEvent); ok {
+				drop = i
+				break
+			}
+		}
+		q.events = append(q.events[:drop], q.events[drop+1:]...)
+	}
+	q.events = append(q.events, e)
+	return room
+}
+
+// Next removes and returns the oldest event, ok is false once the queue is empty
+func (q *EventQueue) Next() (e Event, ok bool) {
+	if len(q.events) == 0 {
+		return nil, false
+	}
+	e = q.events[0]
+	q.events[0] = nil
+	q.events = q.events[1:]
+	return e, true
+}
+
+// Drain returns every queued event, oldest first, and empties the queue
+func (q *EventQueue) Drain() []Event {
+	events := q.events
+	q.events = nil
+	return events
+}
+
+func (q *EventQueue) Len() int {
+	return len(q.events)
+}
+
+// Dropped counts events lost because the queue was full
+func (q *EventQueue) Dropped() int {
+	return q.dropped
+}
+
+// Clear throws away any unread events
+func (q *EventQueue) Clear() {
+	q.events = nil
+}
+
+// Events returns the queue of events received so far. Events are only queued
+// once this has been called, and then stay queued until read, so drain it
+// every frame.
+func (in *Input) Events() *EventQueue {
+	if in.events == nil {
+		in.events = NewEventQueue(defaultEventCapacity)
+	}
+	return in.events
+}
+
+// Dispatch applies an event to the key, mouse and text state, calls the
+// registered handlers and queues it for Events
+func (in *Input) Dispatch(e Event) {
+	switch e := e.(type) {
+	case KeyEvent:
+		in.input.keyEvent(e.Key, e.Action, e.Mods)
+		for _, h := range in.keyHandlers {
+			if h != nil {
+				h(e.Key, e.Action, e.Mods)
+			}
+		}
+	case CharEvent:
+		in.input.text.WriteRune(e.Char)
+		for _, h := range in.charHandlers {
+			if h != nil {
+				h(e.Char)
+			}
+		}
+	case MouseMoveEvent:
+		in.mouse.move(e.X, e.Y)
+	case MouseButtonEvent:
+		in.input.mods = e.Mods
+		in.mouse.button(e.Button, e.Action)
+	case ScrollEvent:
+		in.mouse.scroll(e.X, e.Y)
+	case CursorEnterEvent:
+		in.mouse.enter(e.Entered)
+	case FileDropEvent:
+		for _, h := range in.dropHandlers {
+			if h != nil {
+				h(e.Paths)
+			}
+		}
+	case ResizeEvent:
+		for _, h := range in.resizeHandlers {
+			if h != nil {
+				h(e.Width, e.Height)
+			}
+		}
+	}
+
+	if in.events != nil && !in.events.Push(e) && !in.warnedFull {
+		in.warnedFull = true
+		log.Printf("render: event queue full, dropping events; drain Events() every frame")
+	}
+}
+
+// Dispatch feeds an event through the window as if GLFW had delivered it,
+// recording it if input is being recorded
+func (w *Window) Dispatch(e Event) {
+	w.record(e)
+	w.Input.Dispatch(e)
+}
+
+func (w *Window) installEventCallbacks() {
+	w.handle.SetKeyCallback(func(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		w.dispatchLive(KeyEvent{Key: Key(key), Action: KeyAction(action), Mods: Modifier(mods)})
+	})
+	w.handle.SetCharCallback(func(_ *glfw.Window, char rune) {
+		w.dispatchLive(CharEvent{Char: char})
+	})
+	w.handle.SetFocusCallback(func(_ *glfw.Window, focused bool) {
+		w.dispatchLive(FocusEvent{Focused: focused})
+	})
+	w.handle.SetCloseCallback(func(_ *glfw.Window) {
+		w.dispatchLive(CloseEvent{})
+	})
+	w.handle.SetDropCallback(func(_ *glfw.Window, names []string) {
+		w.dispatchLive(FileDropEvent{Paths: names})
+	})
+}
@@ -0,0 +1,143 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventQueueOrder(t *testing.T) {
+	q := NewEventQueue(8)
+	pushed := []Event{
+		KeyEvent{Key: KeyA, Action: KeyPressed},
+		CharEvent{Char: 'a'},
+		KeyEvent{Key: KeyA, Action: KeyReleased},
+		ScrollEvent{Y: 1},
+	}
+	for _, e := range pushed {
+		if !q.Push(e) {
+			t.Fatalf("Push(%v) reported a drop with room left", e)
+		}
+	}
+
+	var got []Event
+	for {
+		e, ok := q.Next()
+		if !ok {
+			break
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, pushed) {
+		t.Errorf("got %v, want %v", got, pushed)
+	}
+	if q.Len() != 0 || q.Dropped() != 0 {
+		t.Errorf("Len() = %d, Dropped() = %d after draining, want 0, 0", q.Len(), q.Dropped())
+	}
+}
+
+func TestEventQueueDrops(t *testing.T) {
+	tests := []struct {
+		name   string
+		pushed []Event
+		want   []Event
+	}{
+		{
+			name: "oldest mouse move goes first",
+			pushed: []Event{
+				KeyEvent{Key: KeyA, Action: KeyPressed},
+				MouseMoveEvent{X: 1},
+				MouseMoveEvent{X: 2},
+				KeyEvent{Key: KeyA, Action: KeyReleased},
+			},
+			want: []Event{
+				KeyEvent{Key: KeyA, Action: KeyPressed},
+				MouseMoveEvent{X: 2},
+				KeyEvent{Key: KeyA, Action: KeyReleased},
+			},
+		},
+		{
+			name: "oldest event without mouse moves",
+			pushed: []Event{
+				KeyEvent{Key: KeyA, Action: KeyPressed},
+				KeyEvent{Key: KeyB, Action: KeyPressed},
+				KeyEvent{Key: KeyC, Action: KeyPressed},
+				KeyEvent{Key: KeyD, Action: KeyPressed},
+			},
+			want: []Event{
+				KeyEvent{Key: KeyB, Action: KeyPressed},
+				KeyEvent{Key: KeyC, Action: KeyPressed},
+				KeyEvent{Key: KeyD, Action: KeyPressed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewEventQueue(3)
+			for i, e := range tt.pushed {
+				if room := q.Push(e); room != (i < 3) {
+					t.Errorf("Push %d returned %v", i, room)
+				}
+			}
+			if got := q.Drain(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if q.Dropped() != 1 {
+				t.Errorf("Dropped() = %d, want 1", q.Dropped())
+			}
+		})
+	}
+}
+
+func TestInputDispatch(t *testing.T) {
+	in := NewInput()
+	queue := in.Events()
+
+	var keys []Key
+	in.OnKeyPress(func(k Key) { keys = append(keys, k) })
+
+	in.Dispatch(KeyEvent{Key: KeyW, Action: KeyPressed, Mods: ModShift})
+	if !in.Modifiers().Has(ModShift) {
+		t.Error("shift should be held")
+	}
+	in.Dispatch(CharEvent{Char: 'W'})
+	in.Dispatch(MouseMoveEvent{X: 10, Y: 20})
+	in.Dispatch(MouseMoveEvent{X: 15, Y: 18})
+	in.Dispatch(MouseButtonEvent{Button: MouseButtonLeft, Action: KeyPressed})
+
+	if !in.IsKeyDown(KeyW) || !in.WasPressed(KeyW) {
+		t.Error("W should be down and pressed this frame")
+	}
+	if got := in.TypedText(); got != "W" {
+		t.Errorf("TypedText() = %q, want W", got)
+	}
+	if dx, dy := in.CursorDelta(); dx != 5 || dy != -2 {
+		t.Errorf("CursorDelta() = %v, %v, want 5, -2", dx, dy)
+	}
+	if !in.MousePressed(MouseButtonLeft) {
+		t.Error("left button should be pressed this frame")
+	}
+	if !reflect.DeepEqual(keys, []Key{KeyW}) {
+		t.Errorf("OnKeyPress saw %v, want [W]", keys)
+	}
+	if queue.Len() != 5 {
+		t.Errorf("queued %d events, want 5", queue.Len())
+	}
+
+	in.EndFrame()
+	in.Dispatch(KeyEvent{Key: KeyW, Action: KeyReleased})
+
+	if in.IsKeyDown(KeyW) || in.WasPressed(KeyW) || !in.WasReleased(KeyW) {
+		t.Error("W should be up and released this frame")
+	}
+	if in.TypedText() != "" {
+		t.Errorf("TypedText() = %q after EndFrame, want empty", in.TypedText())
+	}
+	if !in.IsMouseDown(MouseButtonLeft) || in.MousePressed(MouseButtonLeft) {
+		t.Error("left button should still be held but not newly pressed")
+	}
+	// unread events survive the end of the frame
+	if queue.Len() != 6 {
+		t.Errorf("queued %d events, want 6", queue.Len())
+	}
+}
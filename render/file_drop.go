@@ -0,0 +1,28 @@
+package render
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// OnFileDrop registers a handler called with the paths of files dragged onto
+// the window. Calling the returned function removes the handler again.
+func (in *Input) OnFileDrop(handler func(paths []string)) (remove func()) {
+	in.dropHandlers = append(in.dropHandlers, handler)
+	i := len(in.dropHandlers) - 1
+	return func() { in.dropHandlers[i] = nil }
+}
+
+// IsTextureFile reports whether NewTextureFromFile can load file, going by its extension
+func IsTextureFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return isCompressedFile(file)
+}
+
+// IsShaderFile reports whether NewProgramFromFile can load file, going by its extension
+func IsShaderFile(file string) bool {
+	return strings.ToLower(filepath.Ext(file)) == ".shader"
+}
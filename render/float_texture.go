@@ -0,0 +1,55 @@
+package render
+
+import (
+	"os"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/kevholditch/opengl-playground/hdr"
+)
+
+// Internal formats accepted by NewFloatTexture
+const (
+	FloatFormatHalf = gl.RGBA16F_ARB
+	FloatFormatFull = gl.RGBA32F_ARB
+)
+
+func NewFloatTextureFromFile(file string, format int32, opts TextureOptions) (*Texture, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := hdr.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewFloatTexture(img, format, opts)
+}
+
+// NewFloatTexture uploads unclamped float pixels as an RGBA16F or RGBA32F
+// texture. The data is linear so the sRGB setting in opts is ignored.
+func NewFloatTexture(img *hdr.Image, format int32, opts TextureOptions) (*Texture, error) {
+	opts = opts.withDefaults()
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+
+	texture := Texture{
+		handle: handle,
+		target: gl.TEXTURE_2D,
+		width:  int32(img.Rect.Dx()),
+		height: int32(img.Rect.Dy()),
+	}
+	texture.Bind(0)
+
+	opts.apply(texture.target)
+
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, int32(img.Stride/4))
+	gl.TexImage2D(texture.target, 0, format, texture.width, texture.height, 0, gl.RGBA, gl.FLOAT, gl.Ptr(img.Pix))
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
+
+	opts.generateMipmaps(texture.target)
+
+	return &texture, nil
+}
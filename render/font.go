@@ -0,0 +1,39 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kevholditch/opengl-playground/bmfont"
+)
+
+// Font is a BMFont with its page textures uploaded
+type Font struct {
+	*bmfont.Font
+	Pages []*Texture
+}
+
+// NewFontFromFile loads a text or binary .fnt file and the page images it
+// names, which are resolved relative to the .fnt file
+func NewFontFromFile(file string, opts TextureOptions) (*Font, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bf, err := bmfont.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+
+	font := &Font{Font: bf}
+	for _, page := range bf.Pages {
+		tex, err := NewTextureFromFile(filepath.Join(filepath.Dir(file), page), opts)
+		if err != nil {
+			return nil, err
+		}
+		font.Pages = append(font.Pages, tex)
+	}
+	return font, nil
+}
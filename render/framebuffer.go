@@ -0,0 +1,209 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+type DepthAttachment int
+
+const (
+	DepthNone         DepthAttachment = iota
+	DepthRenderbuffer                 // depth and stencil that are only tested against, never sampled
+	DepthTexture                      // depth and stencil stored in a texture that can be sampled
+)
+
+type FramebufferConfig struct {
+	Width  int
+	Height int
+
+	// ColorFormats holds one internal format per color attachment, for example
+	// gl.RGBA8 or FloatFormatHalf. Defaults to a single gl.RGBA8 attachment.
+	ColorFormats []int32
+	Depth        DepthAttachment
+
+	// Options sets how the color attachments are sampled once rendered to
+	Options TextureOptions
+}
+
+// Framebuffer is an offscreen render target whose attachments are textures
+type Framebuffer struct {
+	handle       uint32
+	cfg          FramebufferConfig
+	colors       []*Texture
+	depthTexture *Texture
+	depthBuffer  uint32
+	prevViewport [4]int32
+	prevBinding  int32
+}
+
+var framebufferStatuses = map[uint32]string{
+	gl.FRAMEBUFFER_UNDEFINED:                     "no default framebuffer exists",
+	gl.FRAMEBUFFER_INCOMPLETE_ATTACHMENT:         "an attachment is incomplete",
+	gl.FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT: "no images are attached",
+	gl.FRAMEBUFFER_INCOMPLETE_DRAW_BUFFER:        "a draw buffer has no attachment",
+	gl.FRAMEBUFFER_INCOMPLETE_READ_BUFFER:        "the read buffer has no attachment",
+	gl.FRAMEBUFFER_UNSUPPORTED:                   "the combination of attachment formats is not supported",
+	gl.FRAMEBUFFER_INCOMPLETE_MULTISAMPLE:        "attachments have mismatched sample counts",
+	gl.FRAMEBUFFER_INCOMPLETE_LAYER_TARGETS_ARB:  "attachments are not all layered",
+}
+
+func NewFramebuffer(cfg FramebufferConfig) (*Framebuffer, error) {
+	if len(cfg.ColorFormats) == 0 {
+		cfg.ColorFormats = []int32{gl.RGBA8}
+	}
+	cfg.Options = cfg.Options.withDefaults()
+
+	fb := &Framebuffer{cfg: cfg}
+	gl.GenFramebuffers(1, &fb.handle)
+
+	if err := fb.attach(); err != nil {
+		fb.Delete()
+		return nil, err
+	}
+	return fb, nil
+}
+
+func (fb *Framebuffer) attach() error {
+	w, h := int32(fb.cfg.Width), int32(fb.cfg.Height)
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("framebuffer size %dx%d is invalid", w, h)
+	}
+
+	var prev int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &prev)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.handle)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(prev))
+
+	drawBuffers := make([]uint32, len(fb.cfg.ColorFormats))
+	for i, format := range fb.cfg.ColorFormats {
+		tex := newEmptyTexture(w, h, format, colorPixelType(format), gl.RGBA, fb.cfg.Options)
+		attachment := uint32(gl.COLOR_ATTACHMENT0 + i)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, attachment, tex.target, tex.handle, 0)
+		fb.colors = append(fb.colors, tex)
+		drawBuffers[i] = attachment
+	}
+	gl.DrawBuffers(int32(len(drawBuffers)), &drawBuffers[0])
+
+	switch fb.cfg.Depth {
+	case DepthRenderbuffer:
+		gl.GenRenderbuffers(1, &fb.depthBuffer)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, fb.depthBuffer)
+		gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, w, h)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, fb.depthBuffer)
+	case DepthTexture:
+		opts := TextureOptions{MinFilter: gl.NEAREST, MagFilter: gl.NEAREST}.withDefaults()
+		fb.depthTexture = newEmptyTexture(w, h, gl.DEPTH24_STENCIL8, gl.UNSIGNED_INT_24_8, gl.DEPTH_STENCIL, opts)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.TEXTURE_2D, fb.depthTexture.handle, 0)
+	}
+
+	return checkFramebufferStatus()
+}
+
+func checkFramebufferStatus() error {
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	if status == gl.FRAMEBUFFER_COMPLETE {
+		return nil
+	}
+	if reason, ok := framebufferStatuses[status]; ok {
+		return fmt.Errorf("framebuffer incomplete: %s (0x%X)", reason, status)
+	}
+	return fmt.Errorf("framebuffer incomplete: status 0x%X", status)
+}
+
+// newEmptyTexture allocates storage for a texture that will be rendered into
+func newEmptyTexture(width, height, internalFmt int32, pixType, format uint32, opts TextureOptions) *Texture {
+	var handle uint32
+	gl.GenTextures(1, &handle)
+
+	texture := Texture{
+		handle: handle,
+		target: gl.TEXTURE_2D,
+		width:  width,
+		height: height,
+	}
+	texture.Bind(0)
+	opts.apply(texture.target)
+	gl.TexImage2D(texture.target, 0, internalFmt, width, height, 0, format, pixType, nil)
+	texture.UnBind()
+
+	return &texture
+}
+
+func colorPixelType(format int32) uint32 {
+	switch format {
+	case gl.RGBA16F_ARB, gl.RGBA32F_ARB, gl.R16F, gl.R32F, gl.RG16F:
+		return gl.FLOAT
+	}
+	return gl.UNSIGNED_BYTE
+}
+
+// Bind directs rendering into the framebuffer and sets the viewport to cover
+// it. Unbind restores the previous framebuffer and viewport.
+func (fb *Framebuffer) Bind() {
+	checkThread()
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &fb.prevBinding)
+	gl.GetIntegerv(gl.VIEWPORT, &fb.prevViewport[0])
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.handle)
+	gl.Viewport(0, 0, int32(fb.cfg.Width), int32(fb.cfg.Height))
+}
+
+func (fb *Framebuffer) Unbind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(fb.prevBinding))
+	gl.Viewport(fb.prevViewport[0], fb.prevViewport[1], fb.prevViewport[2], fb.prevViewport[3])
+}
+
+// Resize recreates every attachment at the new size. Textures previously
+// returned by ColorAttachment are deleted.
+func (fb *Framebuffer) Resize(width, height int) error {
+	if width == fb.cfg.Width && height == fb.cfg.Height {
+		return nil
+	}
+	fb.deleteAttachments()
+	fb.cfg.Width, fb.cfg.Height = width, height
+	return fb.attach()
+}
+
+func (fb *Framebuffer) Width() int {
+	return fb.cfg.Width
+}
+
+func (fb *Framebuffer) Height() int {
+	return fb.cfg.Height
+}
+
+func (fb *Framebuffer) ColorAttachment(i int) *Texture {
+	return fb.colors[i]
+}
+
+func (fb *Framebuffer) ColorAttachments() []*Texture {
+	return fb.colors
+}
+
+// DepthTexture is nil unless the framebuffer was created with DepthTexture
+func (fb *Framebuffer) DepthTexture() *Texture {
+	return fb.depthTexture
+}
+
+func (fb *Framebuffer) Delete() {
+	fb.deleteAttachments()
+	gl.DeleteFramebuffers(1, &fb.handle)
+	fb.handle = 0
+}
+
+func (fb *Framebuffer) deleteAttachments() {
+	for _, tex := range fb.colors {
+		tex.Delete()
+	}
+	fb.colors = nil
+	if fb.depthTexture != nil {
+		fb.depthTexture.Delete()
+		fb.depthTexture = nil
+	}
+	if fb.depthBuffer != 0 {
+		gl.DeleteRenderbuffers(1, &fb.depthBuffer)
+		fb.depthBuffer = 0
+	}
+}
@@ -0,0 +1,337 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Joystick is a controller slot, from 0 up to MaxJoysticks-1
+type Joystick int
+
+const MaxJoysticks = int(glfw.JoystickLast-glfw.Joystick1) + 1
+
+type GamepadButton int
+
+// Buttons in the standard gamepad layout, named for an Xbox controller
+const (
+	GamepadA           = GamepadButton(glfw.ButtonA)
+	GamepadB           = GamepadButton(glfw.ButtonB)
+	GamepadX           = GamepadButton(glfw.ButtonX)
+	GamepadY           = GamepadButton(glfw.ButtonY)
+	GamepadLeftBumper  = GamepadButton(glfw.ButtonLeftBumper)
+	GamepadRightBumper = GamepadButton(glfw.ButtonRightBumper)
+	GamepadBack        = GamepadButton(glfw.ButtonBack)
+	GamepadStart       = GamepadButton(glfw.ButtonStart)
+	GamepadGuide       = GamepadButton(glfw.ButtonGuide)
+	GamepadLeftThumb   = GamepadButton(glfw.ButtonLeftThumb)
+	GamepadRightThumb  = GamepadButton(glfw.ButtonRightThumb)
+	GamepadDpadUp      = GamepadButton(glfw.ButtonDpadUp)
+	GamepadDpadRight   = GamepadButton(glfw.ButtonDpadRight)
+	GamepadDpadDown    = GamepadButton(glfw.ButtonDpadDown)
+	GamepadDpadLeft    = GamepadButton(glfw.ButtonDpadLeft)
+)
+
+type GamepadAxis int
+
+const (
+	GamepadLeftX        = GamepadAxis(glfw.AxisLeftX)
+	GamepadLeftY        = GamepadAxis(glfw.AxisLeftY)
+	GamepadRightX       = GamepadAxis(glfw.AxisRightX)
+	GamepadRightY       = GamepadAxis(glfw.AxisRightY)
+	GamepadLeftTrigger  = GamepadAxis(glfw.AxisLeftTrigger)
+	GamepadRightTrigger = GamepadAxis(glfw.AxisRightTrigger)
+)
+
+var gamepadButtonNames = map[GamepadButton]string{
+	GamepadA:           "A",
+	GamepadB:           "B",
+	GamepadX:           "X",
+	GamepadY:           "Y",
+	GamepadLeftBumper:  "LeftBumper",
+	GamepadRightBumper: "RightBumper",
+	GamepadBack:        "Back",
+	GamepadStart:       "Start",
+	GamepadGuide:       "Guide",
+	GamepadLeftThumb:   "LeftThumb",
+	GamepadRightThumb:  "RightThumb",
+	GamepadDpadUp:      "DpadUp",
+	GamepadDpadRight:   "DpadRight",
+	GamepadDpadDown:    "DpadDown",
+	GamepadDpadLeft:    "DpadLeft",
+}
+
+var gamepadAxisNames = map[GamepadAxis]string{
+	GamepadLeftX:        "LeftX",
+	GamepadLeftY:        "LeftY",
+	GamepadRightX:       "RightX",
+	GamepadRightY:       "RightY",
+	GamepadLeftTrigger:  "LeftTrigger",
+	GamepadRightTrigger: "RightTrigger",
+}
+
+func (b GamepadButton) String() string {
+	if name, ok := gamepadButtonNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("GamepadButton(%d)", int(b))
+}
+
+func (a GamepadAxis) String() string {
+	if name, ok := gamepadAxisNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("GamepadAxis(%d)", int(a))
+}
+
+const (
+	defaultDeadZone = 0.15
+
+	// maxDeadZone keeps some stick travel outside the dead zone, which
+	// radialDeadZone rescales by 1 - deadZone
+	maxDeadZone = 0.95
+
+	// axisActionThreshold is how far an axis must be pushed to count as an action
+	axisActionThreshold = 0.5
+)
+
+var errBadMapping = errors.New("glfw rejected the gamepad mappings")
+
+var gamepadListeners []func(joy Joystick, connected bool)
+
+// OnGamepadConnection registers a handler called as controllers are plugged
+// in or removed
+func OnGamepadConnection(handler func(joy Joystick, connected bool)) {
+	if gamepadListeners == nil {
+		glfw.SetJoystickCallback(func(joy glfw.Joystick, event glfw.PeripheralEvent) {
+			for _, l := range gamepadListeners {
+				l(Joystick(joy-glfw.Joystick1), event == glfw.Connected)
+			}
+		})
+	}
+	gamepadListeners = append(gamepadListeners, handler)
+}
+
+// UpdateGamepadMappings adds SDL_GameControllerDB style mapping strings, one
+// controller per line, so more controllers report the standard layout
+func UpdateGamepadMappings(mappings string) error {
+	if !glfw.UpdateGamepadMappings(mappings) {
+		return errBadMapping
+	}
+	return nil
+}
+
+// LoadGamepadMappings reads a gamecontrollerdb.txt style file
+func LoadGamepadMappings(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return UpdateGamepadMappings(string(data))
+}
+
+func (j Joystick) glfw() glfw.Joystick {
+	return glfw.Joystick1 + glfw.Joystick(j)
+}
+
+func (j Joystick) Present() bool {
+	return j.glfw().Present()
+}
+
+// IsGamepad reports whether the joystick has a standard gamepad mapping
+func (j Joystick) IsGamepad() bool {
+	return j.glfw().IsGamepad()
+}
+
+func (j Joystick) Name() string {
+	if j.IsGamepad() {
+		return j.glfw().GetGamepadName()
+	}
+	return j.glfw().GetName()
+}
+
+// Axes and Buttons give raw joystick input for devices without a gamepad mapping
+func (j Joystick) Axes() []float32 {
+	return j.glfw().GetAxes()
+}
+
+func (j Joystick) Buttons() []bool {
+	actions := j.glfw().GetButtons()
+	buttons := make([]bool, len(actions))
+	for i, a := range actions {
+		buttons[i] = a == glfw.Press
+	}
+	return buttons
+}
+
+type padState struct {
+	connected bool
+	buttons   [glfw.ButtonLast + 1]bool
+	axes      [glfw.AxisLast + 1]float32
+}
+
+// gamepadState keeps this and last frame's state of every gamepad so presses
+// can be detected. It reads the controllers the first time it is asked each frame.
+type gamepadState struct {
+	current  [MaxJoysticks]padState
+	previous [MaxJoysticks]padState
+	deadZone float32
+	fresh    bool
+}
+
+func newGamepadState() *gamepadState {
+	return &gamepadState{deadZone: defaultDeadZone}
+}
+
+func (g *gamepadState) update() {
+	if g.fresh {
+		return
+	}
+	g.fresh = true
+	for i := range g.current {
+		g.current[i] = readPad(Joystick(i), g.deadZone)
+	}
+}
+
+func (g *gamepadState) endFrame() {
+	g.update()
+	g.previous = g.current
+	g.fresh = false
+}
+
+func readPad(j Joystick, deadZone float32) padState {
+	var p padState
+	if !j.Present() || !j.IsGamepad() {
+		return p
+	}
+	s := j.glfw().GetGamepadState()
+	if s == nil {
+		return p
+	}
+	p.connected = true
+	for i, a := range s.Buttons {
+		if i < len(p.buttons) {
+			p.buttons[i] = a == glfw.Press
+		}
+	}
+	copy(p.axes[:], s.Axes[:])
+
+	lx, ly := radialDeadZone(p.axes[glfw.AxisLeftX], p.axes[glfw.AxisLeftY], deadZone)
+	p.axes[glfw.AxisLeftX], p.axes[glfw.AxisLeftY] = lx, ly
+	rx, ry := radialDeadZone(p.axes[glfw.AxisRightX], p.axes[glfw.AxisRightY], deadZone)
+	p.axes[glfw.AxisRightX], p.axes[glfw.AxisRightY] = rx, ry
+
+	// triggers rest at -1; report them from 0 released to 1 fully pulled
+	for _, t := range []glfw.GamepadAxis{glfw.AxisLeftTrigger, glfw.AxisRightTrigger} {
+		v := (p.axes[t] + 1) / 2
+		if v < deadZone {
+			v = 0
+		}
+		p.axes[t] = v
+	}
+	return p
+}
+
+// radialDeadZone zeroes small stick movements and rescales the rest so output
+// still starts at zero just outside the dead zone
+func radialDeadZone(x, y, deadZone float32) (float32, float32) {
+	mag := float32(math.Hypot(float64(x), float64(y)))
+	if mag <= deadZone {
+		return 0, 0
+	}
+	scaled := (mag - deadZone) / (1 - deadZone)
+	if scaled > 1 {
+		scaled = 1
+	}
+	return x / mag * scaled, y / mag * scaled
+}
+
+// SetGamepadDeadZone sets how far sticks and triggers move before they register,
+// 0.15 by default. Values are clamped to between 0 and 0.95.
+func (w *Window) SetGamepadDeadZone(deadZone float32) {
+	w.pads.deadZone = clampDeadZone(deadZone)
+}
+
+func clampDeadZone(deadZone float32) float32 {
+	// written so NaN also ends up as 0
+	if !(deadZone > 0) {
+		return 0
+	}
+	if deadZone > maxDeadZone {
+		return maxDeadZone
+	}
+	return deadZone
+}
+
+// GamepadConnected reports whether a controller with a gamepad mapping is in slot j
+func (w *Window) GamepadConnected(j Joystick) bool {
+	w.pads.update()
+	return w.pads.current[j].connected
+}
+
+func (w *Window) IsGamepadButtonDown(j Joystick, b GamepadButton) bool {
+	w.pads.update()
+	return w.pads.current[j].buttons[b]
+}
+
+// GamepadButtonPressed reports whether b went down since the last SwapBuffers
+func (w *Window) GamepadButtonPressed(j Joystick, b GamepadButton) bool {
+	w.pads.update()
+	return w.pads.current[j].buttons[b] && !w.pads.previous[j].buttons[b]
+}
+
+// GamepadButtonReleased reports whether b came up since the last SwapBuffers
+func (w *Window) GamepadButtonReleased(j Joystick, b GamepadButton) bool {
+	w.pads.update()
+	return !w.pads.current[j].buttons[b] && w.pads.previous[j].buttons[b]
+}
+
+// GamepadAxis returns a stick axis from -1 to 1, or a trigger from 0 to 1,
+// with the dead zone applied
+func (w *Window) GamepadAxis(j Joystick, a GamepadAxis) float32 {
+	w.pads.update()
+	return w.pads.current[j].axes[a]
+}
+
+// AxisBinding triggers an action when an axis is pushed past halfway in one direction
+type AxisBinding struct {
+	Axis     GamepadAxis
+	Negative bool
+}
+
+func (b AxisBinding) active(p padState) bool {
+	v := p.axes[b.Axis]
+	if b.Negative {
+		return v <= -axisActionThreshold
+	}
+	return v >= axisActionThreshold
+}
+
+// parseGamepadBinding reads names such as "Gamepad.A", "Gamepad.DpadLeft" or
+// "Gamepad.LeftX-" from an action map file
+func parseGamepadBinding(name string) (interface{}, bool, error) {
+	const prefix = "gamepad."
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasPrefix(lower, prefix) {
+		return nil, false, nil
+	}
+	rest := lower[len(prefix):]
+
+	for b, n := range gamepadButtonNames {
+		if strings.ToLower(n) == rest {
+			return b, true, nil
+		}
+	}
+	if strings.HasSuffix(rest, "+") || strings.HasSuffix(rest, "-") {
+		for a, n := range gamepadAxisNames {
+			if strings.ToLower(n) == rest[:len(rest)-1] {
+				return AxisBinding{Axis: a, Negative: strings.HasSuffix(rest, "-")}, true, nil
+			}
+		}
+	}
+	return nil, true, fmt.Errorf("unknown gamepad input %q", name)
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClampDeadZone(t *testing.T) {
+	tests := []struct {
+		in, want float32
+	}{
+		{0, 0},
+		{0.2, 0.2},
+		{-0.5, 0},
+		{1, maxDeadZone},
+		{3, maxDeadZone},
+		{float32(math.NaN()), 0},
+	}
+	for _, tt := range tests {
+		if got := clampDeadZone(tt.in); got != tt.want {
+			t.Errorf("clampDeadZone(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRadialDeadZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     float32
+		deadZone float32
+		wantX    float32
+		wantY    float32
+	}{
+		{"inside", 0.1, 0.1, 0.2, 0, 0},
+		{"edge", 0.2, 0, 0.2, 0, 0},
+		{"halfway", 0, -0.6, 0.2, 0, -0.5},
+		{"full", 1, 0, 0.2, 1, 0},
+		{"past full is capped", 1, 1, 0.2, float32(math.Sqrt2 / 2), float32(math.Sqrt2 / 2)},
+		{"largest dead zone", 0.975, 0, maxDeadZone, 0.5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := radialDeadZone(tt.x, tt.y, tt.deadZone)
+			if math.Abs(float64(x-tt.wantX)) > 1e-5 || math.Abs(float64(y-tt.wantY)) > 1e-5 {
+				t.Errorf("got %v, %v, want %v, %v", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
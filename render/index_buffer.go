@@ -15,6 +15,7 @@ type IndexBuffer struct {
 }
 
 func NewIndexBuffer(indices []int32) *IndexBuffer {
+	checkThread()
 	var ibo uint32
 	gl.GenBuffers(1, &ibo)
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ibo)
@@ -23,11 +24,23 @@ func NewIndexBuffer(indices []int32) *IndexBuffer {
 	return &IndexBuffer{handle: ibo, count: int32(len(indices))}
 }
 
+// NewQuadIndexBuffer builds indices for quadCount quads laid out as four
+// vertices each, in the same winding the examples use
+func NewQuadIndexBuffer(quadCount int) *IndexBuffer {
+	indices := make([]int32, 0, quadCount*6)
+	for i := int32(0); i < int32(quadCount); i++ {
+		v := i * 4
+		indices = append(indices, v, v+1, v+2, v, v+3, v+2)
+	}
+	return NewIndexBuffer(indices)
+}
+
 func (ib *IndexBuffer) GetCount() int32 {
 	return ib.count
 }
 
 func (ib *IndexBuffer) Bind() {
+	checkThread()
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ib.handle)
 }
 
@@ -4,9 +4,15 @@ import (
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"log"
+	"runtime"
 )
 
+// Initialise starts GLFW and GL on the calling goroutine and locks it to its OS
+// thread. Lock it in init as the examples do so it is the main thread, which
+// GLFW requires on macOS.
 func Initialise() func() {
+	runtime.LockOSThread()
+	setGLGoroutine()
 	if err := glfw.Init(); err != nil {
 		log.Fatalln("failed to initialize glfw:", err)
 	}
@@ -20,3 +26,24 @@ func UseDefaultBlending() {
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 }
+
+// UseSeamlessCubeMaps makes cube maps sample across face edges instead of
+// clamping to each face, which hides seams in skyboxes. It applies to every
+// cube map in the current context, needs GL 3.2 or ARB_seamless_cube_map, and
+// is called by NewCubeMap.
+func UseSeamlessCubeMaps() {
+	if glVersionAtLeast(3, 2) || glfw.ExtensionSupported("GL_ARB_seamless_cube_map") {
+		gl.Enable(gl.TEXTURE_CUBE_MAP_SEAMLESS)
+	}
+}
+
+// glVersionAtLeast reports whether the current context's GL version is at
+// least major.minor
+func glVersionAtLeast(major, minor int) bool {
+	w := glfw.GetCurrentContext()
+	if w == nil {
+		return false
+	}
+	ctxMajor, ctxMinor := w.GetAttrib(glfw.ContextVersionMajor), w.GetAttrib(glfw.ContextVersionMinor)
+	return ctxMajor > major || ctxMajor == major && ctxMinor >= minor
+}
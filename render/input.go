@@ -0,0 +1,244 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Input is the key, mouse and text state of a window along with its event
+// handlers. It is updated by Dispatch and needs no window, so tests can drive
+// it with synthetic events. Window embeds one.
+type Input struct {
+	input  *inputState
+	mouse  *mouseState
+	events *EventQueue
+
+	keyHandlers    []func(key Key, action KeyAction, mods Modifier)
+	charHandlers   []func(char rune)
+	dropHandlers   []func(paths []string)
+	resizeHandlers []func(width, height int)
+	warnedFull     bool
+}
+
+func NewInput() *Input {
+	return &Input{input: newInputState(), mouse: newMouseState()}
+}
+
+// EndFrame forgets this frame's presses, releases, movement and typed text.
+// SwapBuffers calls it for a window.
+func (in *Input) EndFrame() {
+	in.input.endFrame()
+	in.mouse.endFrame()
+}
+
+// inputState tracks which keys are held and which changed since the last
+// frame, and the text typed this frame
+type inputState struct {
+	down     map[Key]bool
+	pressed  map[Key]bool
+	released map[Key]bool
+	mods     Modifier
+	text     strings.Builder
+}
+
+func newInputState() *inputState {
+	return &inputState{
+		down:     map[Key]bool{},
+		pressed:  map[Key]bool{},
+		released: map[Key]bool{},
+	}
+}
+
+func (s *inputState) keyEvent(key Key, action KeyAction, mods Modifier) {
+	s.mods = mods
+	switch action {
+	case KeyPressed:
+		s.down[key] = true
+		s.pressed[key] = true
+	case KeyReleased:
+		delete(s.down, key)
+		s.released[key] = true
+	}
+}
+
+// endFrame forgets this frame's presses and releases
+func (s *inputState) endFrame() {
+	for k := range s.pressed {
+		delete(s.pressed, k)
+	}
+	for k := range s.released {
+		delete(s.released, k)
+	}
+	s.text.Reset()
+}
+
+// IsKeyDown reports whether key is currently held
+func (in *Input) IsKeyDown(key Key) bool {
+	return in.input.down[key]
+}
+
+// WasPressed reports whether key went down since the last SwapBuffers
+func (in *Input) WasPressed(key Key) bool {
+	return in.input.pressed[key]
+}
+
+// WasReleased reports whether key came up since the last SwapBuffers
+func (in *Input) WasReleased(key Key) bool {
+	return in.input.released[key]
+}
+
+// Modifiers returns the modifier keys held at the last key event
+func (in *Input) Modifiers() Modifier {
+	return in.input.mods
+}
+
+// ActionMap binds named actions such as "move_left" to keys and gamepad inputs
+type ActionMap struct {
+	keys    map[string][]Key
+	buttons map[string][]GamepadButton
+	axes    map[string][]AxisBinding
+}
+
+func NewActionMap() *ActionMap {
+	return &ActionMap{
+		keys:    map[string][]Key{},
+		buttons: map[string][]GamepadButton{},
+		axes:    map[string][]AxisBinding{},
+	}
+}
+
+// LoadActionMap reads a JSON file mapping action names to key names, or to
+// gamepad buttons and axis directions prefixed with "Gamepad.":
+//
+//	{"move_left": ["A", "Left", "Gamepad.DpadLeft", "Gamepad.LeftX-"], "jump": ["Space", "Gamepad.A"]}
+func LoadActionMap(file string) (*ActionMap, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var bindings map[string][]string
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	m := NewActionMap()
+	for action, names := range bindings {
+		for _, name := range names {
+			if err := m.bindName(action, name); err != nil {
+				return nil, fmt.Errorf("%s: action %s: %v", file, action, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Bind adds keys to an action; any of them triggers it
+func (m *ActionMap) Bind(action string, keys ...Key) *ActionMap {
+	m.keys[action] = append(m.keys[action], keys...)
+	return m
+}
+
+func (m *ActionMap) bindName(action, name string) error {
+	binding, isGamepad, err := parseGamepadBinding(name)
+	if err != nil {
+		return err
+	}
+	if isGamepad {
+		switch b := binding.(type) {
+		case GamepadButton:
+			m.BindGamepadButton(action, b)
+		case AxisBinding:
+			m.BindGamepadAxis(action, b)
+		}
+		return nil
+	}
+
+	k, err := ParseKey(name)
+	if err != nil {
+		return err
+	}
+	m.Bind(action, k)
+	return nil
+}
+
+// BindGamepadButton adds buttons, on any connected gamepad, to an action
+func (m *ActionMap) BindGamepadButton(action string, buttons ...GamepadButton) *ActionMap {
+	m.buttons[action] = append(m.buttons[action], buttons...)
+	return m
+}
+
+// BindGamepadAxis adds stick or trigger directions, on any connected gamepad, to an action
+func (m *ActionMap) BindGamepadAxis(action string, axes ...AxisBinding) *ActionMap {
+	m.axes[action] = append(m.axes[action], axes...)
+	return m
+}
+
+func (m *ActionMap) Keys(action string) []Key {
+	return m.keys[action]
+}
+
+func (w *Window) SetActionMap(m *ActionMap) {
+	w.actions = m
+}
+
+// IsActionDown reports whether any key or gamepad input bound to action is held
+func (w *Window) IsActionDown(action string) bool {
+	return w.anyActionKey(action, w.IsKeyDown) || w.anyActionPad(action, func(now, before bool) bool {
+		return now
+	})
+}
+
+// ActionPressed reports whether any input bound to action went down this frame
+func (w *Window) ActionPressed(action string) bool {
+	return w.anyActionKey(action, w.WasPressed) || w.anyActionPad(action, func(now, before bool) bool {
+		return now && !before
+	})
+}
+
+// ActionReleased reports whether any input bound to action came up this frame
+func (w *Window) ActionReleased(action string) bool {
+	return w.anyActionKey(action, w.WasReleased) || w.anyActionPad(action, func(now, before bool) bool {
+		return !now && before
+	})
+}
+
+func (w *Window) anyActionKey(action string, test func(Key) bool) bool {
+	if w.actions == nil {
+		return false
+	}
+	for _, k := range w.actions.Keys(action) {
+		if test(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyActionPad tests the action's gamepad bindings on every connected pad,
+// passing test whether each input is active this frame and last frame
+func (w *Window) anyActionPad(action string, test func(now, before bool) bool) bool {
+	if w.actions == nil || len(w.actions.buttons[action])+len(w.actions.axes[action]) == 0 {
+		return false
+	}
+	w.pads.update()
+	for j := range w.pads.current {
+		cur, prev := w.pads.current[j], w.pads.previous[j]
+		if !cur.connected && !prev.connected {
+			continue
+		}
+		for _, b := range w.actions.buttons[action] {
+			if test(cur.buttons[b], prev.buttons[b]) {
+				return true
+			}
+		}
+		for _, a := range w.actions.axes[action] {
+			if test(a.active(cur), a.active(prev)) {
+				return true
+			}
+		}
+	}
+	return false
+}
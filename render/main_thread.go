@@ -0,0 +1,137 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// glGoroutine is the goroutine that called Initialise, which locks it to its
+// OS thread so it stands in for the GL thread. checkThread reads it from any
+// goroutine so it is only accessed atomically, as is threadChecks.
+var glGoroutine uint64
+
+var threadChecks int32
+
+// SetThreadChecks turns on a debug mode where render functions panic when they
+// are called from any goroutine other than the one that called Initialise.
+// Finding the caller's goroutine is slow, so leave this off in release builds.
+func SetThreadChecks(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&threadChecks, v)
+}
+
+func setGLGoroutine() {
+	atomic.StoreUint64(&glGoroutine, goroutineID())
+}
+
+func checkThread() {
+	if atomic.LoadInt32(&threadChecks) == 0 {
+		return
+	}
+	owner := atomic.LoadUint64(&glGoroutine)
+	if owner == 0 {
+		return
+	}
+	if id := goroutineID(); id != owner {
+		panic(fmt.Sprintf("render: GL called from goroutine %d, must run on the GL thread (goroutine %d); use MainThread.Call", id, owner))
+	}
+}
+
+// goroutineID parses the current goroutine's id from its stack header
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// MainThread runs functions on the GL thread for other goroutines. The render
+// loop drains queued calls with Process each frame, or hands control to Run.
+type MainThread struct {
+	calls chan func()
+	id    uint64
+}
+
+// NewMainThread makes a runner for the calling goroutine and locks it to its
+// OS thread. Call it from the goroutine that called Initialise, which panics
+// otherwise, since GLFW and the context are already tied to that thread.
+func NewMainThread() *MainThread {
+	runtime.LockOSThread()
+	id := goroutineID()
+	if owner := atomic.LoadUint64(&glGoroutine); owner != 0 && owner != id {
+		panic(fmt.Sprintf("render: NewMainThread called from goroutine %d, Initialise ran on goroutine %d", id, owner))
+	}
+	return &MainThread{calls: make(chan func(), 64), id: id}
+}
+
+// Call runs fn on the GL thread and waits for it to finish. Called from the GL
+// thread itself, fn runs immediately.
+func (m *MainThread) Call(fn func()) {
+	if goroutineID() == m.id {
+		fn()
+		return
+	}
+	done := make(chan struct{})
+	m.calls <- func() {
+		defer close(done)
+		fn()
+	}
+	<-done
+}
+
+// CallErr runs fn on the GL thread and returns its error
+func (m *MainThread) CallErr(fn func() error) error {
+	var err error
+	m.Call(func() {
+		err = fn()
+	})
+	return err
+}
+
+// Process runs every call queued so far. Call it once per frame from the render loop.
+func (m *MainThread) Process() {
+	for {
+		select {
+		case fn := <-m.calls:
+			fn()
+		default:
+			return
+		}
+	}
+}
+
+// Run starts run on a new goroutine and services calls on the GL thread until
+// it returns, for programs that keep their main loop off the GL thread
+func (m *MainThread) Run(run func()) {
+	if id := goroutineID(); id != m.id {
+		panic(fmt.Sprintf("render: MainThread.Run called from goroutine %d, not the GL thread (goroutine %d)", id, m.id))
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run()
+	}()
+
+	for {
+		select {
+		case fn := <-m.calls:
+			fn()
+		case <-done:
+			m.Process()
+			return
+		}
+	}
+}
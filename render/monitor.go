@@ -0,0 +1,134 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+var ErrNoMonitor = errors.New("no monitors connected")
+
+type VideoMode struct {
+	Width, Height                int
+	RedBits, GreenBits, BlueBits int
+	RefreshRate                  int
+}
+
+func (m VideoMode) String() string {
+	return fmt.Sprintf("%dx%d@%dHz", m.Width, m.Height, m.RefreshRate)
+}
+
+type Monitor struct {
+	Index   int
+	Name    string
+	Primary bool
+	// X and Y are the monitor's position on the virtual desktop
+	X, Y    int
+	Current VideoMode
+	Modes   []VideoMode
+}
+
+// Monitors lists the connected monitors in the order Config.Monitor indexes them,
+// the primary monitor first
+func Monitors() []Monitor {
+	var monitors []Monitor
+	for i, m := range glfw.GetMonitors() {
+		x, y := m.GetPos()
+		info := Monitor{
+			Index:   i,
+			Name:    m.GetName(),
+			Primary: i == 0,
+			X:       x,
+			Y:       y,
+			Current: toVideoMode(m.GetVideoMode()),
+		}
+		for _, mode := range m.GetVideoModes() {
+			info.Modes = append(info.Modes, toVideoMode(mode))
+		}
+		monitors = append(monitors, info)
+	}
+	return monitors
+}
+
+func toVideoMode(m *glfw.VidMode) VideoMode {
+	return VideoMode{
+		Width:       m.Width,
+		Height:      m.Height,
+		RedBits:     m.RedBits,
+		GreenBits:   m.GreenBits,
+		BlueBits:    m.BlueBits,
+		RefreshRate: m.RefreshRate,
+	}
+}
+
+func monitorAt(index int) (*glfw.Monitor, error) {
+	monitors := glfw.GetMonitors()
+	if len(monitors) == 0 {
+		return nil, ErrNoMonitor
+	}
+	if index < 0 || index >= len(monitors) {
+		return nil, fmt.Errorf("monitor %d not found, %d connected", index, len(monitors))
+	}
+	return monitors[index], nil
+}
+
+// fullscreenMode picks the video mode to use on a monitor, zero values keep
+// the desktop's current mode
+func fullscreenMode(m *glfw.Monitor, width, height, refreshRate int) (int, int, int) {
+	current := m.GetVideoMode()
+	if width == 0 || height == 0 {
+		width, height = current.Width, current.Height
+	}
+	if refreshRate == 0 {
+		refreshRate = current.RefreshRate
+	}
+	return width, height, refreshRate
+}
+
+// IsFullscreen reports whether the window is covering a monitor, either
+// fullscreen or as a borderless window
+func (w *Window) IsFullscreen() bool {
+	return w.fullscreen
+}
+
+// SetFullscreen switches between covering the configured monitor and the
+// window's previous size and position. With Config.Borderless the window is
+// made undecorated and sized to the desktop, otherwise it goes exclusive
+// fullscreen at the monitor's current video mode, or the mode Width and
+// Height pick when Config.Fullscreen was set.
+func (w *Window) SetFullscreen(fullscreen bool) error {
+	if fullscreen == w.fullscreen {
+		return nil
+	}
+	if !fullscreen {
+		r := w.windowed
+		w.handle.SetMonitor(nil, r.x, r.y, r.width, r.height, 0)
+		w.handle.SetAttrib(glfw.Decorated, glfwBool(!w.cfg.Undecorated))
+		w.fullscreen = false
+		return nil
+	}
+
+	m, err := monitorAt(w.cfg.Monitor)
+	if err != nil {
+		return err
+	}
+	w.windowed.x, w.windowed.y = w.handle.GetPos()
+	w.windowed.width, w.windowed.height = w.handle.GetSize()
+
+	if w.cfg.Borderless {
+		mode := m.GetVideoMode()
+		mx, my := m.GetPos()
+		w.handle.SetAttrib(glfw.Decorated, glfw.False)
+		w.handle.SetMonitor(nil, mx, my, mode.Width, mode.Height, 0)
+	} else {
+		var width, height int
+		if w.cfg.Fullscreen {
+			width, height = w.cfg.Width, w.cfg.Height
+		}
+		width, height, refresh := fullscreenMode(m, width, height, w.cfg.RefreshRate)
+		w.handle.SetMonitor(m, 0, 0, width, height, refresh)
+	}
+	w.fullscreen = true
+	return nil
+}
@@ -0,0 +1,173 @@
+package render
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+type MouseButton int
+
+const (
+	MouseButtonLeft   = MouseButton(glfw.MouseButtonLeft)
+	MouseButtonRight  = MouseButton(glfw.MouseButtonRight)
+	MouseButtonMiddle = MouseButton(glfw.MouseButtonMiddle)
+	MouseButton4      = MouseButton(glfw.MouseButton4)
+	MouseButton5      = MouseButton(glfw.MouseButton5)
+)
+
+type CursorMode int
+
+const (
+	CursorNormal   CursorMode = iota
+	CursorHidden              // invisible while over the window but otherwise free
+	CursorCaptured            // hidden and locked to the window for FPS style look, read movement from CursorDelta
+)
+
+// mouseState tracks the cursor and buttons, with per frame deltas like inputState
+type mouseState struct {
+	x, y           float64
+	dx, dy         float64
+	scrollX        float64
+	scrollY        float64
+	inside         bool
+	hasPosition    bool
+	down           map[MouseButton]bool
+	pressed        map[MouseButton]bool
+	released       map[MouseButton]bool
+	enterListeners []func(entered bool)
+}
+
+func newMouseState() *mouseState {
+	return &mouseState{
+		down:     map[MouseButton]bool{},
+		pressed:  map[MouseButton]bool{},
+		released: map[MouseButton]bool{},
+	}
+}
+
+func (m *mouseState) move(x, y float64) {
+	if m.hasPosition {
+		m.dx += x - m.x
+		m.dy += y - m.y
+	}
+	m.x, m.y = x, y
+	m.hasPosition = true
+}
+
+func (m *mouseState) button(b MouseButton, action KeyAction) {
+	switch action {
+	case KeyPressed:
+		m.down[b] = true
+		m.pressed[b] = true
+	case KeyReleased:
+		delete(m.down, b)
+		m.released[b] = true
+	}
+}
+
+func (m *mouseState) scroll(x, y float64) {
+	m.scrollX += x
+	m.scrollY += y
+}
+
+func (m *mouseState) enter(entered bool) {
+	m.inside = entered
+	for _, l := range m.enterListeners {
+		l(entered)
+	}
+}
+
+func (m *mouseState) endFrame() {
+	m.dx, m.dy = 0, 0
+	m.scrollX, m.scrollY = 0, 0
+	for b := range m.pressed {
+		delete(m.pressed, b)
+	}
+	for b := range m.released {
+		delete(m.released, b)
+	}
+}
+
+func (w *Window) installMouseCallbacks() {
+	w.mouse.x, w.mouse.y = w.handle.GetCursorPos()
+	w.mouse.hasPosition = true
+
+	w.handle.SetCursorPosCallback(func(_ *glfw.Window, x, y float64) {
+		w.dispatchLive(MouseMoveEvent{X: x, Y: y})
+	})
+	w.handle.SetMouseButtonCallback(func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		w.dispatchLive(MouseButtonEvent{Button: MouseButton(button), Action: KeyAction(action), Mods: Modifier(mods)})
+	})
+	w.handle.SetScrollCallback(func(_ *glfw.Window, x, y float64) {
+		w.dispatchLive(ScrollEvent{X: x, Y: y})
+	})
+	w.handle.SetCursorEnterCallback(func(_ *glfw.Window, entered bool) {
+		w.dispatchLive(CursorEnterEvent{Entered: entered})
+	})
+}
+
+// CursorPos is the cursor position in window coordinates, from the top left
+func (in *Input) CursorPos() (float64, float64) {
+	return in.mouse.x, in.mouse.y
+}
+
+// FramebufferCursorPos is the cursor position in framebuffer pixels, which
+// differ from window coordinates on HiDPI displays
+func (w *Window) FramebufferCursorPos() (float64, float64) {
+	ww, wh := w.handle.GetSize()
+	fw, fh := w.handle.GetFramebufferSize()
+	if ww == 0 || wh == 0 {
+		return w.mouse.x, w.mouse.y
+	}
+	return w.mouse.x * float64(fw) / float64(ww), w.mouse.y * float64(fh) / float64(wh)
+}
+
+// CursorDelta is how far the cursor moved since the last SwapBuffers
+func (in *Input) CursorDelta() (float64, float64) {
+	return in.mouse.dx, in.mouse.dy
+}
+
+// ScrollDelta is how far the wheel or trackpad scrolled since the last SwapBuffers
+func (in *Input) ScrollDelta() (float64, float64) {
+	return in.mouse.scrollX, in.mouse.scrollY
+}
+
+func (in *Input) IsMouseDown(b MouseButton) bool {
+	return in.mouse.down[b]
+}
+
+// MousePressed reports whether b went down since the last SwapBuffers
+func (in *Input) MousePressed(b MouseButton) bool {
+	return in.mouse.pressed[b]
+}
+
+// MouseReleased reports whether b came up since the last SwapBuffers
+func (in *Input) MouseReleased(b MouseButton) bool {
+	return in.mouse.released[b]
+}
+
+// CursorInside reports whether the cursor is over the window
+func (in *Input) CursorInside() bool {
+	return in.mouse.inside
+}
+
+// OnCursorEnter registers a handler called as the cursor enters or leaves the window
+func (in *Input) OnCursorEnter(handler func(entered bool)) {
+	in.mouse.enterListeners = append(in.mouse.enterListeners, handler)
+}
+
+func (w *Window) SetCursorMode(mode CursorMode) {
+	switch mode {
+	case CursorHidden:
+		w.handle.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+	case CursorCaptured:
+		w.handle.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+		// unaccelerated motion feels better for camera look where it is available
+		if glfw.RawMouseMotionSupported() {
+			w.handle.SetInputMode(glfw.RawMouseMotion, glfw.True)
+		}
+	default:
+		w.handle.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	}
+	// the cursor jumps when the mode changes, which should not read as movement
+	w.mouse.hasPosition = false
+}
@@ -0,0 +1,233 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// DefaultTimeStep is the frame time used for playback when a recording has none
+const DefaultTimeStep = 1.0 / 60
+
+var ErrNotRecording = errors.New("window is not recording input")
+
+// RecordedEvent is an input event and the frame it arrived in. Events for
+// frame n arrive after the swap that ends frame n-1.
+type RecordedEvent struct {
+	Frame int
+	Event Event
+}
+
+// InputRecording is a session of input events that can be saved and played
+// back. Gamepads are polled rather than sent as events so are not recorded.
+type InputRecording struct {
+	// Step is the time each frame advances by on playback, in seconds
+	Step float64 `json:"step"`
+	// Frames is how many frames were recorded, playback lasts this long
+	Frames int `json:"frames"`
+	// CursorX and CursorY are where the cursor was when recording started
+	CursorX float64         `json:"cursorX"`
+	CursorY float64         `json:"cursorY"`
+	Events  []RecordedEvent `json:"events"`
+}
+
+type recordedEventJSON struct {
+	Frame int             `json:"frame"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// recordableEvents are the input events, by the name used in recording files.
+// Window events such as resize and focus describe the real window so are not replayed.
+var recordableEvents = map[string]func() Event{
+	"key":         func() Event { return &KeyEvent{} },
+	"char":        func() Event { return &CharEvent{} },
+	"mouseMove":   func() Event { return &MouseMoveEvent{} },
+	"mouseButton": func() Event { return &MouseButtonEvent{} },
+	"scroll":      func() Event { return &ScrollEvent{} },
+	"cursorEnter": func() Event { return &CursorEnterEvent{} },
+	"fileDrop":    func() Event { return &FileDropEvent{} },
+}
+
+func eventTypeName(e Event) (string, bool) {
+	switch e.(type) {
+	case KeyEvent:
+		return "key", true
+	case CharEvent:
+		return "char", true
+	case MouseMoveEvent:
+		return "mouseMove", true
+	case MouseButtonEvent:
+		return "mouseButton", true
+	case ScrollEvent:
+		return "scroll", true
+	case CursorEnterEvent:
+		return "cursorEnter", true
+	case FileDropEvent:
+		return "fileDrop", true
+	}
+	return "", false
+}
+
+func isInputEvent(e Event) bool {
+	_, ok := eventTypeName(e)
+	return ok
+}
+
+func (r RecordedEvent) MarshalJSON() ([]byte, error) {
+	name, ok := eventTypeName(r.Event)
+	if !ok {
+		return nil, fmt.Errorf("cannot record %T", r.Event)
+	}
+	data, err := json.Marshal(r.Event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(recordedEventJSON{Frame: r.Frame, Type: name, Data: data})
+}
+
+func (r *RecordedEvent) UnmarshalJSON(b []byte) error {
+	var raw recordedEventJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	newEvent, ok := recordableEvents[raw.Type]
+	if !ok {
+		return fmt.Errorf("unknown event type %q", raw.Type)
+	}
+	e := newEvent()
+	if err := json.Unmarshal(raw.Data, e); err != nil {
+		return fmt.Errorf("%s event: %v", raw.Type, err)
+	}
+	r.Frame = raw.Frame
+	// store the value rather than the pointer so type switches match live events
+	switch e := e.(type) {
+	case *KeyEvent:
+		r.Event = *e
+	case *CharEvent:
+		r.Event = *e
+	case *MouseMoveEvent:
+		r.Event = *e
+	case *MouseButtonEvent:
+		r.Event = *e
+	case *ScrollEvent:
+		r.Event = *e
+	case *CursorEnterEvent:
+		r.Event = *e
+	case *FileDropEvent:
+		r.Event = *e
+	}
+	return nil
+}
+
+// Save writes the recording as JSON
+func (r *InputRecording) Save(file string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+func LoadInputRecording(file string) (*InputRecording, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var r InputRecording
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return &r, nil
+}
+
+// inputPlayback replays a recording's events as frames advance
+type inputPlayback struct {
+	recording  *InputRecording
+	startFrame int
+	next       int
+}
+
+// StartRecording records every input event from now on, with frame numbers
+// counted from zero, until StopRecording. step is saved for playback.
+func (w *Window) StartRecording(step float64) {
+	w.recording = &InputRecording{Step: step, CursorX: w.mouse.x, CursorY: w.mouse.y}
+	w.recordStart = w.frame
+}
+
+// StopRecording ends recording and returns what was recorded
+func (w *Window) StopRecording() (*InputRecording, error) {
+	if w.recording == nil {
+		return nil, ErrNotRecording
+	}
+	r := w.recording
+	r.Frames = w.frame - w.recordStart
+	w.recording = nil
+	return r, nil
+}
+
+// RecordingInput reports whether input is being recorded
+func (w *Window) RecordingInput() bool {
+	return w.recording != nil
+}
+
+// Play replays a recording, starting with the events recorded before the
+// first swap. While it plays live input is
+// ignored and DeltaTime advances by the recording's fixed step, so a demo that
+// moves by DeltaTime or per frame repeats exactly.
+func (w *Window) Play(r *InputRecording) {
+	w.playback = &inputPlayback{recording: r, startFrame: w.frame}
+	w.mouse.x, w.mouse.y = r.CursorX, r.CursorY
+	w.mouse.hasPosition = true
+	w.advancePlayback()
+}
+
+// Playing reports whether a recording is still being played back
+func (w *Window) Playing() bool {
+	return w.playback != nil
+}
+
+// StopPlayback returns control to live input
+func (w *Window) StopPlayback() {
+	w.playback = nil
+}
+
+func (w *Window) record(e Event) {
+	if w.recording == nil || !isInputEvent(e) {
+		return
+	}
+	w.recording.Events = append(w.recording.Events, RecordedEvent{Frame: w.frame - w.recordStart, Event: e})
+}
+
+// dispatchLive passes on events from GLFW, except input during playback
+func (w *Window) dispatchLive(e Event) {
+	if w.playback != nil && isInputEvent(e) {
+		return
+	}
+	w.Dispatch(e)
+}
+
+// advancePlayback dispatches the events recorded for the frame about to start
+func (w *Window) advancePlayback() {
+	p := w.playback
+	if p == nil {
+		return
+	}
+	frame := w.frame - p.startFrame
+	events := p.recording.Events
+	for p.next < len(events) && events[p.next].Frame <= frame {
+		w.Dispatch(events[p.next].Event)
+		p.next++
+	}
+	if p.next >= len(events) && frame >= p.recording.Frames {
+		w.playback = nil
+	}
+}
+
+func (w *Window) playbackStep() float64 {
+	if w.playback.recording.Step > 0 {
+		return w.playback.recording.Step
+	}
+	return DefaultTimeStep
+}
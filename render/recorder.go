@@ -0,0 +1,133 @@
+package render
+
+import (
+	"image"
+	"os"
+	"time"
+
+	"github.com/kevholditch/opengl-playground/capture"
+)
+
+// Recorder grabs a run of frames from a window or framebuffer, for example
+// NewRecorder(w.Screenshot) or NewRecorder(fb.ReadPixels)
+type Recorder struct {
+	grab      func() (*image.RGBA, error)
+	frames    []image.Image
+	delays    []time.Duration
+	remaining int // frames left when recording a count
+	timed     bool
+	until     time.Time
+	last      time.Time
+	recording bool
+}
+
+func NewRecorder(grab func() (*image.RGBA, error)) *Recorder {
+	return &Recorder{grab: grab}
+}
+
+// RecordFrames starts capturing the next n frames, dropping any earlier
+// recording. It does nothing when n is not positive.
+func (r *Recorder) RecordFrames(n int) {
+	if n <= 0 {
+		return
+	}
+	r.start()
+	r.remaining = n
+}
+
+// RecordFor starts capturing every frame for the given duration
+func (r *Recorder) RecordFor(d time.Duration) {
+	r.start()
+	r.timed = true
+	r.until = time.Now().Add(d)
+}
+
+func (r *Recorder) start() {
+	r.frames = nil
+	r.delays = nil
+	r.last = time.Time{}
+	r.remaining = 0
+	r.timed = false
+	r.recording = true
+}
+
+func (r *Recorder) Stop() {
+	r.recording = false
+}
+
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Capture grabs the current frame while a recording is running. Call it once
+// per frame, before SwapBuffers when recording a window.
+func (r *Recorder) Capture() error {
+	if !r.recording {
+		return nil
+	}
+	now := time.Now()
+	if r.timed && now.After(r.until) {
+		r.recording = false
+		return nil
+	}
+
+	img, err := r.grab()
+	if err != nil {
+		r.recording = false
+		return err
+	}
+
+	// each frame is shown until the next one was captured
+	if len(r.frames) > 0 {
+		r.delays[len(r.delays)-1] = now.Sub(r.last)
+	}
+	r.frames = append(r.frames, img)
+	r.delays = append(r.delays, 0)
+	r.last = now
+
+	if !r.timed {
+		r.remaining--
+		if r.remaining == 0 {
+			r.recording = false
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) Frames() []image.Image {
+	return r.frames
+}
+
+// SaveGIF writes the recorded frames as an animated GIF
+func (r *Recorder) SaveGIF(file string, opts capture.GIFOptions) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	err = capture.WriteGIF(f, r.frames, r.frameDelays(), opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// SaveSequence writes the recorded frames as numbered PNG or PPM files
+func (r *Recorder) SaveSequence(dir, prefix string, format capture.SequenceFormat) ([]string, error) {
+	return capture.WriteSequence(dir, prefix, format, r.frames)
+}
+
+// frameDelays fills in the last frame's delay, which has no following frame
+// to measure against, with the average of the others
+func (r *Recorder) frameDelays() []time.Duration {
+	delays := append([]time.Duration(nil), r.delays...)
+	if len(delays) > 1 {
+		var total time.Duration
+		for _, d := range delays[:len(delays)-1] {
+			total += d
+		}
+		delays[len(delays)-1] = total / time.Duration(len(delays)-1)
+	} else if len(delays) == 1 {
+		delays[0] = time.Second / 30
+	}
+	return delays
+}
@@ -5,13 +5,25 @@ import (
 )
 
 func Clear() {
+	checkThread()
 	gl.Clear(gl.COLOR_BUFFER_BIT)
 }
 
 func Render(va *VertexArray, ib *IndexBuffer, shader *Program) {
+	checkThread()
 	va.Bind()
 	ib.Bind()
 	shader.Bind()
 
 	gl.DrawElements(gl.TRIANGLES, ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
 }
+
+// RenderCount draws only the first count indices of the index buffer
+func RenderCount(va *VertexArray, ib *IndexBuffer, shader *Program, count int32) {
+	checkThread()
+	va.Bind()
+	ib.Bind()
+	shader.Bind()
+
+	gl.DrawElements(gl.TRIANGLES, count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+}
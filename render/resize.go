@@ -0,0 +1,67 @@
+package render
+
+import (
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// installResizeCallbacks keeps the viewport covering the framebuffer. On HiDPI
+// displays the framebuffer has more pixels than the window has screen
+// coordinates, so the viewport is always set from the framebuffer size while
+// resize handlers are given the window size.
+func (w *Window) installResizeCallbacks() {
+	fw, fh := w.handle.GetFramebufferSize()
+	gl.Viewport(0, 0, int32(fw), int32(fh))
+
+	w.handle.SetFramebufferSizeCallback(func(_ *glfw.Window, width, height int) {
+		w.framebufferResized(width, height)
+	})
+	w.handle.SetContentScaleCallback(func(_ *glfw.Window, x, y float32) {
+		for _, h := range w.scaleHandlers {
+			h(x, y)
+		}
+	})
+}
+
+func (w *Window) framebufferResized(fbWidth, fbHeight int) {
+	// a minimised window reports a zero size, there is nothing to draw into
+	if fbWidth == 0 || fbHeight == 0 {
+		return
+	}
+	w.withContext(func() {
+		gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
+	})
+
+	width, height := w.Size()
+	w.dispatchLive(ResizeEvent{Width: width, Height: height, FramebufferWidth: fbWidth, FramebufferHeight: fbHeight})
+}
+
+// OnResize registers a handler called with the new window size, in screen
+// coordinates, whenever the window is resized. Calling the returned function
+// removes the handler again.
+func (in *Input) OnResize(handler func(width, height int)) (remove func()) {
+	in.resizeHandlers = append(in.resizeHandlers, handler)
+	i := len(in.resizeHandlers) - 1
+	return func() { in.resizeHandlers[i] = nil }
+}
+
+// OnContentScaleChange registers a handler called when the window moves to a
+// monitor with a different DPI scale
+func (w *Window) OnContentScaleChange(handler func(x, y float32)) {
+	w.scaleHandlers = append(w.scaleHandlers, handler)
+}
+
+// Size is the window size in screen coordinates
+func (w *Window) Size() (int, int) {
+	return w.handle.GetSize()
+}
+
+// FramebufferSize is the size of the drawable area in pixels
+func (w *Window) FramebufferSize() (int, int) {
+	return w.handle.GetFramebufferSize()
+}
+
+// ContentScale is the ratio of pixels to screen coordinates, 2 on a typical retina display
+func (w *Window) ContentScale() (float32, float32) {
+	return w.handle.GetContentScale()
+}
@@ -0,0 +1,317 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"unsafe"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+var errNoPendingRead = errors.New("no pixel read is pending")
+
+var errEmptyFramebuffer = errors.New("framebuffer is empty, the window may be minimised")
+
+// Screenshot reads back the frame drawn so far. Call it before SwapBuffers,
+// after which the back buffer contents are undefined. It waits for the GPU to
+// finish the frame; StartScreenshot reads through pixel buffer objects instead.
+func (w *Window) Screenshot() (*image.RGBA, error) {
+	width, height := w.handle.GetFramebufferSize()
+	var img *image.RGBA
+	var err error
+	withReadBuffer(0, gl.BACK, func() {
+		img, err = readPixels(width, height)
+	})
+	return img, err
+}
+
+// StartScreenshot queues a read of the frame drawn so far into a pixel buffer
+// object, which PollScreenshot collects once the GPU has finished it, usually
+// a frame or two later. buffers is how many reads can be in flight at once.
+func (w *Window) StartScreenshot(buffers int) error {
+	width, height := w.handle.GetFramebufferSize()
+	if width <= 0 || height <= 0 {
+		return errEmptyFramebuffer
+	}
+	if w.pixelReader == nil {
+		w.pixelReader = NewPixelReader(buffers)
+	}
+	withReadBuffer(0, gl.BACK, func() {
+		w.pixelReader.Start(width, height)
+	})
+	return nil
+}
+
+// PollScreenshot returns the oldest screenshot from StartScreenshot once it is ready
+func (w *Window) PollScreenshot() (*image.RGBA, bool) {
+	if w.pixelReader == nil {
+		return nil, false
+	}
+	return w.pixelReader.Poll()
+}
+
+// ReadPixels reads back the first color attachment
+func (fb *Framebuffer) ReadPixels() (*image.RGBA, error) {
+	return fb.ReadAttachment(0)
+}
+
+func (fb *Framebuffer) ReadAttachment(i int) (*image.RGBA, error) {
+	if i < 0 || i >= len(fb.colors) {
+		return nil, fmt.Errorf("framebuffer has no color attachment %d", i)
+	}
+
+	var img *image.RGBA
+	var err error
+	withReadBuffer(fb.handle, uint32(gl.COLOR_ATTACHMENT0+i), func() {
+		img, err = readPixels(fb.cfg.Width, fb.cfg.Height)
+	})
+	return img, err
+}
+
+// withReadBuffer runs read with the given framebuffer and read buffer
+// selected, then restores the caller's
+func withReadBuffer(framebuffer, buffer uint32, read func()) {
+	var prevFramebuffer, prevBuffer int32
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &prevFramebuffer)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, framebuffer)
+	gl.GetIntegerv(gl.READ_BUFFER, &prevBuffer)
+	gl.ReadBuffer(buffer)
+
+	read()
+
+	gl.ReadBuffer(uint32(prevBuffer))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(prevFramebuffer))
+}
+
+// readPixels synchronously copies the current read buffer into a top-down image
+func readPixels(width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errEmptyFramebuffer
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	// clear errors left by earlier calls so any error seen is from the read
+	for gl.GetError() != gl.NO_ERROR {
+	}
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	readErr := gl.GetError()
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 4)
+	if readErr != gl.NO_ERROR {
+		return nil, fmt.Errorf("reading pixels failed with GL error 0x%x", readErr)
+	}
+
+	flipRows(img)
+	return img, nil
+}
+
+// flipRows turns GL's bottom-up rows into the top-down order images use
+func flipRows(img *image.RGBA) {
+	h := img.Rect.Dy()
+	row := make([]byte, img.Stride)
+	for y := 0; y < h/2; y++ {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(h-1-y)*img.Stride : (h-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+}
+
+// ScreenshotOptions control how SaveScreenshotsOn reads back the frame
+type ScreenshotOptions struct {
+	// PixelBuffers reads through pixel buffer objects so the frame does not
+	// stall, then writes the PNG off the render thread when the read lands
+	PixelBuffers bool
+}
+
+// SaveScreenshotsOn saves a numbered PNG into dir each time key is pressed.
+// The frame is captured when SwapBuffers is next called.
+func (w *Window) SaveScreenshotsOn(key Key, dir string, opts ScreenshotOptions) {
+	w.screenshotDir = dir
+	w.screenshotOpts = opts
+	w.OnKeyPress(func(k Key) {
+		if k == key {
+			w.screenshotPending = true
+		}
+	})
+}
+
+// updateScreenshots is called by SwapBuffers to take a requested screenshot
+// and save any pixel buffer reads that have finished
+func (w *Window) updateScreenshots() {
+	if w.screenshotPending {
+		w.screenshotPending = false
+		if w.screenshotOpts.PixelBuffers {
+			if err := w.StartScreenshot(2); err != nil {
+				log.Println("screenshot failed:", err)
+			}
+		} else {
+			img, err := w.Screenshot()
+			if err != nil {
+				log.Println("screenshot failed:", err)
+			} else {
+				w.saveScreenshot(img)
+			}
+		}
+	}
+	if !w.screenshotOpts.PixelBuffers {
+		return
+	}
+	for {
+		img, ok := w.PollScreenshot()
+		if !ok {
+			return
+		}
+		go w.saveScreenshot(img)
+	}
+}
+
+func (w *Window) saveScreenshot(img *image.RGBA) {
+	file, err := SavePNG(w.screenshotDir, "screenshot", img)
+	if err != nil {
+		log.Println("screenshot failed:", err)
+		return
+	}
+	log.Println("saved screenshot", file)
+}
+
+// SavePNG writes img to the first unused <prefix>-NNNN.png in dir
+func SavePNG(dir, prefix string, img image.Image) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	for n := 1; ; n++ {
+		file := filepath.Join(dir, fmt.Sprintf("%s-%04d.png", prefix, n))
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		err = png.Encode(f, img)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return file, err
+	}
+}
+
+type pixelRead struct {
+	pbo    uint32
+	fence  uintptr
+	width  int
+	height int
+}
+
+// PixelReader reads back frames through pixel buffer objects. Start queues a
+// copy that the GPU completes in the background and Poll collects it once it
+// is ready, usually a frame later, so the render loop never waits on it.
+type PixelReader struct {
+	reads   []*pixelRead
+	pending []*pixelRead
+}
+
+func NewPixelReader(buffers int) *PixelReader {
+	if buffers < 1 {
+		buffers = 2
+	}
+	r := &PixelReader{}
+	for i := 0; i < buffers; i++ {
+		read := &pixelRead{}
+		gl.GenBuffers(1, &read.pbo)
+		r.reads = append(r.reads, read)
+	}
+	return r
+}
+
+// Start queues a read of the bound read framebuffer. When every buffer is
+// still in flight the oldest read is dropped.
+func (r *PixelReader) Start(width, height int) {
+	var read *pixelRead
+	if len(r.reads) > 0 {
+		read, r.reads = r.reads[0], r.reads[1:]
+	} else {
+		read, r.pending = r.pending[0], r.pending[1:]
+		gl.DeleteSync(read.fence)
+	}
+	read.width, read.height = width, height
+
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, read.pbo)
+	gl.BufferData(gl.PIXEL_PACK_BUFFER, width*height*4, nil, gl.STREAM_READ)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 4)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	read.fence = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+
+	r.pending = append(r.pending, read)
+}
+
+// Poll returns the oldest queued read if the GPU has finished it
+func (r *PixelReader) Poll() (*image.RGBA, bool) {
+	if len(r.pending) == 0 {
+		return nil, false
+	}
+	read := r.pending[0]
+
+	var status int32
+	gl.GetSynciv(read.fence, gl.SYNC_STATUS, 1, nil, &status)
+	if status != gl.SIGNALED {
+		return nil, false
+	}
+	r.pending = r.pending[1:]
+	gl.DeleteSync(read.fence)
+
+	img := image.NewRGBA(image.Rect(0, 0, read.width, read.height))
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, read.pbo)
+	ptr := gl.MapBuffer(gl.PIXEL_PACK_BUFFER, gl.READ_ONLY)
+	if ptr != nil {
+		// view the mapping as a slice the size of the image, go 1.15 has no unsafe.Slice
+		var mapped []byte
+		h := (*reflect.SliceHeader)(unsafe.Pointer(&mapped))
+		h.Data, h.Len, h.Cap = uintptr(ptr), len(img.Pix), len(img.Pix)
+		copy(img.Pix, mapped)
+	}
+	gl.UnmapBuffer(gl.PIXEL_PACK_BUFFER)
+	gl.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+
+	r.reads = append(r.reads, read)
+	if ptr == nil {
+		return nil, false
+	}
+	flipRows(img)
+	return img, true
+}
+
+// Wait blocks until the oldest queued read is ready and returns it
+func (r *PixelReader) Wait() (*image.RGBA, error) {
+	if len(r.pending) == 0 {
+		return nil, errNoPendingRead
+	}
+	gl.ClientWaitSync(r.pending[0].fence, gl.SYNC_FLUSH_COMMANDS_BIT, gl.TIMEOUT_IGNORED)
+	img, ok := r.Poll()
+	if !ok {
+		return nil, errors.New("mapping the pixel buffer failed")
+	}
+	return img, nil
+}
+
+func (r *PixelReader) Delete() {
+	for _, read := range r.pending {
+		gl.DeleteSync(read.fence)
+		r.reads = append(r.reads, read)
+	}
+	r.pending = nil
+	for _, read := range r.reads {
+		gl.DeleteBuffers(1, &read.pbo)
+	}
+	r.reads = nil
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"image"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/kevholditch/opengl-playground/sdf"
+)
+
+// SDFFragmentShader draws signed distance field textures made by the sdf
+// package. It pairs with the text vertex shader: position, texCoord and a fill
+// color per vertex.
+const SDFFragmentShader = `#version 410 core
+
+layout(location = 0) out vec4 color;
+
+in vec2 v_TexCoord;
+in vec4 v_Color;
+
+uniform sampler2D u_Texture;
+uniform float u_Softness;
+uniform float u_OutlineWidth;
+uniform vec4 u_OutlineColor;
+uniform float u_GlowWidth;
+uniform vec4 u_GlowColor;
+
+void main()
+{
+	float dist = texture(u_Texture, v_TexCoord).a;
+	float edge = max(fwidth(dist) * 0.5, u_Softness);
+
+	float fill = smoothstep(0.5 - edge, 0.5 + edge, dist);
+	float outlineEdge = 0.5 - u_OutlineWidth;
+	float outline = smoothstep(outlineEdge - edge, outlineEdge + edge, dist);
+
+	vec4 body = vec4(mix(u_OutlineColor.rgb, v_Color.rgb, fill), mix(u_OutlineColor.a * outline, v_Color.a, fill));
+
+	float glow = smoothstep(outlineEdge - u_GlowWidth, outlineEdge, dist) * u_GlowColor.a;
+	color = mix(vec4(u_GlowColor.rgb, glow), body, body.a);
+}`
+
+// SDFParams are the uniforms of SDFFragmentShader. Widths are in distance field
+// units, where 0.5 spans the field's full spread.
+type SDFParams struct {
+	Softness     float32
+	OutlineWidth float32
+	OutlineColor mgl32.Vec4
+	GlowWidth    float32
+	GlowColor    mgl32.Vec4
+}
+
+// Apply sets the parameters on a bound program using SDFFragmentShader
+func (s SDFParams) Apply(p *Program) {
+	p.SetUniformF1("u_Softness", s.Softness)
+	p.SetUniformF1("u_OutlineWidth", s.OutlineWidth)
+	p.SetUniformVec4("u_OutlineColor", s.OutlineColor[0], s.OutlineColor[1], s.OutlineColor[2], s.OutlineColor[3])
+	p.SetUniformF1("u_GlowWidth", s.GlowWidth)
+	p.SetUniformVec4("u_GlowColor", s.GlowColor[0], s.GlowColor[1], s.GlowColor[2], s.GlowColor[3])
+}
+
+// NewSDFTexture generates a distance field from the mask and uploads it. The
+// field is always stored linearly as it holds distances, not colors.
+func NewSDFTexture(mask *image.Alpha, spread int, opts TextureOptions) (*Texture, error) {
+	opts.Linear = true
+	return NewTexture(sdf.Generate(mask, spread), opts)
+}
+
+// NewSDFTextRenderer draws text from fonts whose pages are distance fields
+func NewSDFTextRenderer(proj mgl32.Mat4, params SDFParams) (*TextRenderer, error) {
+	r, err := newTextRenderer(proj, SDFFragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	r.sdf = &params
+	return r, nil
+}
+
+func (r *TextRenderer) SetSDFParams(params SDFParams) {
+	r.sdf = &params
+}
+
+// NewSDFProgram builds a program for drawing distance field icons. It takes the
+// same vertex layout as text: position(2) texCoord(2) color(4).
+func NewSDFProgram() (*Program, error) {
+	vs, err := NewShaderFromSource(textVertexShader, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := NewShaderFromSource(SDFFragmentShader, gl.FRAGMENT_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	return NewProgram(vs, fs)
+}
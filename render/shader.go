@@ -1,9 +1,12 @@
 package render
 
 import (
+	"errors"
+	"fmt"
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"io/ioutil"
+	"strings"
 )
 
 type Shader struct {
@@ -16,13 +19,21 @@ func NewShaderFromFile(file string, sType uint32) (*Shader, error) {
 	if err != nil {
 		return nil, err
 	}
+	return compileShader(string(src), sType, "SHADER::COMPILE_FAILURE::"+file)
+}
+
+func NewShaderFromSource(src string, sType uint32) (*Shader, error) {
+	return compileShader(src, sType, "SHADER::COMPILE_FAILURE")
+}
+
+func compileShader(src string, sType uint32, failMsg string) (*Shader, error) {
+	checkThread()
 	handle := gl.CreateShader(sType)
-	glSrc, freeFn := gl.Strs(string(src) + "\x00")
+	glSrc, freeFn := gl.Strs(src + "\x00")
 	defer freeFn()
 	gl.ShaderSource(handle, 1, glSrc, nil)
 	gl.CompileShader(handle)
-	err = getGlError(handle, gl.COMPILE_STATUS, gl.GetShaderiv, gl.GetShaderInfoLog,
-		"SHADER::COMPILE_FAILURE::"+file)
+	err := getGlError(handle, gl.COMPILE_STATUS, gl.GetShaderiv, gl.GetShaderInfoLog, failMsg)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +45,10 @@ type Program struct {
 	uniformCache map[string]int32
 }
 
+// NewProgram links the shaders into a program and deletes them. A link failure
+// returns the driver's info log rather than a program that draws nothing.
 func NewProgram(shaders ...*Shader) (*Program, error) {
+	checkThread()
 	handle := gl.CreateProgram()
 
 	for _, shader := range shaders {
@@ -48,10 +62,89 @@ func NewProgram(shaders ...*Shader) (*Program, error) {
 		gl.DeleteShader(shader.Handle)
 	}
 
+	if err := getGlError(handle, gl.LINK_STATUS, gl.GetProgramiv, gl.GetProgramInfoLog, "PROGRAM::LINK_FAILURE"); err != nil {
+		gl.DeleteProgram(handle)
+		return nil, err
+	}
+
 	return &Program{Handle: handle, uniformCache: map[string]int32{}}, nil
 }
 
+// NewProgramFromFile builds a program from a single .shader file holding every
+// stage, each starting with a "#shader vertex" or "#shader fragment" line
+func NewProgramFromFile(file string) (*Program, error) {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := splitShaderSource(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	var shaders []*Shader
+	for _, stage := range stages {
+		shader, err := compileShader(stage.src, stage.sType, "SHADER::COMPILE_FAILURE::"+file)
+		if err != nil {
+			for _, s := range shaders {
+				gl.DeleteShader(s.Handle)
+			}
+			return nil, err
+		}
+		shaders = append(shaders, shader)
+	}
+	return NewProgram(shaders...)
+}
+
+var errNoShaderStages = errors.New("no #shader stages found")
+
+type shaderStage struct {
+	sType uint32
+	src   string
+}
+
+var shaderStageNames = map[string]uint32{
+	"vertex":   gl.VERTEX_SHADER,
+	"fragment": gl.FRAGMENT_SHADER,
+}
+
+func splitShaderSource(src string) ([]shaderStage, error) {
+	var stages []shaderStage
+	for _, line := range strings.SplitAfter(src, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "#shader" {
+			if len(fields) < 2 {
+				return nil, errors.New("#shader needs a stage name")
+			}
+			sType, ok := shaderStageNames[fields[1]]
+			if !ok {
+				return nil, fmt.Errorf("unknown shader stage %q", fields[1])
+			}
+			stages = append(stages, shaderStage{sType: sType})
+			continue
+		}
+		if len(stages) > 0 {
+			stages[len(stages)-1].src += line
+		}
+	}
+	if len(stages) == 0 {
+		return nil, errNoShaderStages
+	}
+	return stages, nil
+}
+
+// IsCombinedShaderFile reports whether file uses #shader lines to hold several stages
+func IsCombinedShaderFile(file string) (bool, error) {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	_, err = splitShaderSource(string(src))
+	return err != errNoShaderStages, nil
+}
+
 func (p *Program) Bind() {
+	checkThread()
 	gl.UseProgram(p.Handle)
 }
 
@@ -59,7 +152,14 @@ func (p *Program) UnBind() {
 	gl.UseProgram(0)
 }
 
+// Delete frees the program; it must not be bound or used afterwards
+func (p *Program) Delete() {
+	checkThread()
+	gl.DeleteProgram(p.Handle)
+}
+
 func (p *Program) getUniformLocation(name string) int32 {
+	checkThread()
 	v, ok := p.uniformCache[name]
 	if ok {
 		return v
@@ -74,6 +174,10 @@ func (p *Program) SetUniformVec4(name string, v0, v1, v2, v3 float32) {
 	gl.Uniform4f(p.getUniformLocation(name), v0, v1, v2, v3)
 }
 
+func (p *Program) SetUniformF1(name string, v0 float32) {
+	gl.Uniform1f(p.getUniformLocation(name), v0)
+}
+
 func (p *Program) SetUniformI1(name string, v0 int32) {
 	gl.Uniform1i(p.getUniformLocation(name), v0)
 }
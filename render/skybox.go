@@ -0,0 +1,103 @@
+package render
+
+import (
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const skyboxVertexShader = `#version 410 core
+
+layout(location = 0) in vec3 position;
+
+uniform mat4 u_VP;
+
+out vec3 v_Direction;
+
+void main()
+{
+	v_Direction = position;
+	// z = w puts every fragment on the far plane, behind the rest of the scene
+	gl_Position = (u_VP * vec4(position, 1.0)).xyww;
+}`
+
+const skyboxFragmentShader = `#version 410 core
+
+layout(location = 0) out vec4 color;
+
+in vec3 v_Direction;
+
+uniform samplerCube u_Skybox;
+
+void main()
+{
+	color = texture(u_Skybox, v_Direction);
+}`
+
+// Skybox draws a cube map around the camera
+type Skybox struct {
+	cubeMap *CubeMap
+	program *Program
+	va      *VertexArray
+	ib      *IndexBuffer
+}
+
+func NewSkybox(cubeMap *CubeMap) (*Skybox, error) {
+	vs, err := NewShaderFromSource(skyboxVertexShader, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := NewShaderFromSource(skyboxFragmentShader, gl.FRAGMENT_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	program, err := NewProgram(vs, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := []float32{
+		-1, -1, -1,
+		1, -1, -1,
+		1, 1, -1,
+		-1, 1, -1,
+		-1, -1, 1,
+		1, -1, 1,
+		1, 1, 1,
+		-1, 1, 1,
+	}
+
+	indices := []int32{
+		0, 2, 1, 0, 3, 2, // -Z
+		4, 5, 6, 4, 6, 7, // +Z
+		0, 4, 7, 0, 7, 3, // -X
+		1, 2, 6, 1, 6, 5, // +X
+		0, 1, 5, 0, 5, 4, // -Y
+		3, 7, 6, 3, 6, 2, // +Y
+	}
+
+	va := NewVertexArray()
+	ib := NewIndexBuffer(indices)
+	va.AddBuffer(NewVertexBuffer(positions), NewVertexBufferLayout().AddLayoutFloats(3))
+	va.UnBind()
+
+	return &Skybox{cubeMap: cubeMap, program: program, va: va, ib: ib}, nil
+}
+
+// Draw renders the skybox using only the rotation of the view matrix so the
+// sky stays put as the camera moves. Call it after the opaque scene.
+func (s *Skybox) Draw(view, proj mgl32.Mat4) {
+	rotation := view.Mat3().Mat4()
+
+	gl.DepthFunc(gl.LEQUAL)
+	gl.DepthMask(false)
+
+	s.program.Bind()
+	s.cubeMap.Bind(0)
+	s.program.SetUniformI1("u_Skybox", 0)
+	s.program.SetUniformMat4f("u_VP", proj.Mul4(rotation))
+
+	Render(s.va, s.ib, s.program)
+
+	gl.DepthMask(true)
+	gl.DepthFunc(gl.LESS)
+}
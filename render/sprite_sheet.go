@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kevholditch/opengl-playground/atlas"
+)
+
+// SpriteSheet is a texture split into numbered frames
+type SpriteSheet struct {
+	Texture *Texture
+	frames  []atlas.Region
+	names   map[string]int
+}
+
+// NewGridSpriteSheet splits the texture into equally sized frames, numbered
+// left to right, top to bottom
+func NewGridSpriteSheet(tex *Texture, frameWidth, frameHeight int) (*SpriteSheet, error) {
+	if frameWidth <= 0 || frameHeight <= 0 || frameWidth > tex.Width() || frameHeight > tex.Height() {
+		return nil, fmt.Errorf("frame size %dx%d does not fit texture of %dx%d", frameWidth, frameHeight, tex.Width(), tex.Height())
+	}
+
+	cols, rows := tex.Width()/frameWidth, tex.Height()/frameHeight
+	w, h := float32(tex.Width()), float32(tex.Height())
+
+	sheet := &SpriteSheet{Texture: tex, names: map[string]int{}}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x, y := col*frameWidth, row*frameHeight
+			sheet.frames = append(sheet.frames, atlas.Region{
+				X:  x,
+				Y:  y,
+				W:  frameWidth,
+				H:  frameHeight,
+				U0: float32(x) / w,
+				V0: float32(y) / h,
+				U1: float32(x+frameWidth) / w,
+				V1: float32(y+frameHeight) / h,
+			})
+		}
+	}
+	return sheet, nil
+}
+
+// NewAtlasSpriteSheet uses the named atlas regions as frames in the order given,
+// or every region sorted by name when no names are passed
+func NewAtlasSpriteSheet(ta *TextureAtlas, names ...string) (*SpriteSheet, error) {
+	if len(names) == 0 {
+		for name := range ta.Regions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	sheet := &SpriteSheet{Texture: ta.Texture, names: map[string]int{}}
+	for i, name := range names {
+		r, err := ta.Region(name)
+		if err != nil {
+			return nil, err
+		}
+		sheet.frames = append(sheet.frames, r)
+		sheet.names[name] = i
+	}
+	return sheet, nil
+}
+
+func (s *SpriteSheet) FrameCount() int {
+	return len(s.frames)
+}
+
+func (s *SpriteSheet) Frame(i int) atlas.Region {
+	return s.frames[i]
+}
+
+// FrameIndex looks up a frame by its atlas region name
+func (s *SpriteSheet) FrameIndex(name string) (int, bool) {
+	i, ok := s.names[name]
+	return i, ok
+}
@@ -0,0 +1,135 @@
+package render
+
+import (
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const textVertexShader = `#version 410 core
+
+layout(location = 0) in vec2 position;
+layout(location = 1) in vec2 texCoord;
+layout(location = 2) in vec4 color;
+
+uniform mat4 u_MVP;
+
+out vec2 v_TexCoord;
+out vec4 v_Color;
+
+void main()
+{
+	gl_Position = u_MVP * vec4(position, 0.0, 1.0);
+	v_TexCoord = texCoord;
+	v_Color = color;
+}`
+
+const textFragmentShader = `#version 410 core
+
+layout(location = 0) out vec4 color;
+
+in vec2 v_TexCoord;
+in vec4 v_Color;
+
+uniform sampler2D u_Texture;
+
+void main()
+{
+	color = v_Color * texture(u_Texture, v_TexCoord);
+}`
+
+const (
+	maxTextQuads     = 4096
+	textVertexFloats = 8 // position(2) texCoord(2) color(4)
+)
+
+// TextRenderer draws BMFont text with one quad per glyph
+type TextRenderer struct {
+	program  *Program
+	va       *VertexArray
+	vb       *VertexBuffer
+	ib       *IndexBuffer
+	proj     mgl32.Mat4
+	sdf      *SDFParams
+	vertices []float32
+}
+
+func NewTextRenderer(proj mgl32.Mat4) (*TextRenderer, error) {
+	return newTextRenderer(proj, textFragmentShader)
+}
+
+func newTextRenderer(proj mgl32.Mat4, fragmentShader string) (*TextRenderer, error) {
+	vs, err := NewShaderFromSource(textVertexShader, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := NewShaderFromSource(fragmentShader, gl.FRAGMENT_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	program, err := NewProgram(vs, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	va := NewVertexArray()
+	vb := NewDynamicVertexBuffer(maxTextQuads * 4 * textVertexFloats)
+	va.AddBuffer(vb, NewVertexBufferLayout().AddLayoutFloats(2).AddLayoutFloats(2).AddLayoutFloats(4))
+	ib := NewQuadIndexBuffer(maxTextQuads)
+	va.UnBind()
+
+	return &TextRenderer{
+		program: program,
+		va:      va,
+		vb:      vb,
+		ib:      ib,
+		proj:    proj,
+	}, nil
+}
+
+func (r *TextRenderer) SetProjection(proj mgl32.Mat4) {
+	r.proj = proj
+}
+
+// DrawText draws text with the baseline of its first line at pos
+func (r *TextRenderer) DrawText(font *Font, text string, pos mgl32.Vec2, color mgl32.Vec4, scale float32) {
+	quads := font.Layout(text, pos.X(), pos.Y(), scale)
+
+	r.program.Bind()
+	r.program.SetUniformMat4f("u_MVP", r.proj)
+	r.program.SetUniformI1("u_Texture", 0)
+	if r.sdf != nil {
+		r.sdf.Apply(r.program)
+	}
+
+	for page, tex := range font.Pages {
+		r.vertices = r.vertices[:0]
+		count := 0
+		for _, q := range quads {
+			if q.Page != page {
+				continue
+			}
+			r.vertices = append(r.vertices,
+				q.X0, q.Y0, q.U0, q.V1, color[0], color[1], color[2], color[3],
+				q.X1, q.Y0, q.U1, q.V1, color[0], color[1], color[2], color[3],
+				q.X1, q.Y1, q.U1, q.V0, color[0], color[1], color[2], color[3],
+				q.X0, q.Y1, q.U0, q.V0, color[0], color[1], color[2], color[3],
+			)
+			count++
+			if count == maxTextQuads {
+				r.flush(tex, count)
+				r.vertices = r.vertices[:0]
+				count = 0
+			}
+		}
+		r.flush(tex, count)
+	}
+}
+
+func (r *TextRenderer) flush(tex *Texture, quadCount int) {
+	if quadCount == 0 {
+		return
+	}
+	tex.Bind(0)
+	r.vb.SetData(r.vertices)
+	RenderCount(r.va, r.ib, r.program, int32(quadCount*6))
+}
@@ -0,0 +1,139 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// OnChar registers a handler for each unicode character typed. Characters
+// come after keyboard layout, dead keys and IME composition are applied, so
+// an IME commit of several characters arrives as several calls. Calling the
+// returned function removes the handler again.
+func (in *Input) OnChar(handler func(char rune)) (remove func()) {
+	in.charHandlers = append(in.charHandlers, handler)
+	i := len(in.charHandlers) - 1
+	return func() { in.charHandlers[i] = nil }
+}
+
+// TypedText is the text typed since the last SwapBuffers, which may be more
+// than one character when an IME commits a word
+func (in *Input) TypedText() string {
+	return in.input.text.String()
+}
+
+// Clipboard returns the system clipboard as text, empty if it holds something else
+func (w *Window) Clipboard() string {
+	checkThread()
+	return glfw.GetClipboardString()
+}
+
+// SetClipboard replaces the system clipboard with text
+func (w *Window) SetClipboard(text string) {
+	checkThread()
+	glfw.SetClipboardString(text)
+}
+
+// TextInput is an editable line of text for consoles and text fields. It
+// reads typed characters and editing keys from its window while Focused.
+type TextInput struct {
+	Focused bool
+	// OnSubmit is called with the text when Enter is pressed
+	OnSubmit func(text string)
+
+	window  *Window
+	runes   []rune
+	cursor  int
+	removes []func()
+}
+
+// NewTextInput creates a focused, empty input listening to w's keys and
+// characters until Close is called
+func NewTextInput(w *Window) *TextInput {
+	t := &TextInput{Focused: true, window: w}
+	t.removes = []func(){w.OnChar(t.char), w.OnKey(t.key)}
+	return t
+}
+
+// Close stops the input listening to its window. The text is kept, and
+// closing twice does nothing.
+func (t *TextInput) Close() {
+	for _, remove := range t.removes {
+		remove()
+	}
+	t.removes = nil
+}
+
+// Text returns the text typed so far
+func (t *TextInput) Text() string {
+	return string(t.runes)
+}
+
+// SetText replaces the text and moves the cursor to the end
+func (t *TextInput) SetText(text string) {
+	t.runes = []rune(text)
+	t.cursor = len(t.runes)
+}
+
+// Clear empties the text and moves the cursor to the start
+func (t *TextInput) Clear() {
+	t.SetText("")
+}
+
+// Cursor is the insertion point, in characters from the start
+func (t *TextInput) Cursor() int {
+	return t.cursor
+}
+
+func (t *TextInput) char(char rune) {
+	if t.Focused {
+		t.insert(string(char))
+	}
+}
+
+func (t *TextInput) insert(text string) {
+	inserted := []rune(text)
+	runes := make([]rune, 0, len(t.runes)+len(inserted))
+	runes = append(runes, t.runes[:t.cursor]...)
+	runes = append(runes, inserted...)
+	t.runes = append(runes, t.runes[t.cursor:]...)
+	t.cursor += len(inserted)
+}
+
+func (t *TextInput) key(key Key, action KeyAction, mods Modifier) {
+	if !t.Focused || action == KeyReleased {
+		return
+	}
+
+	// Command on macOS does what Control does elsewhere
+	shortcut := mods.Has(ModControl) || mods.Has(ModSuper)
+
+	switch {
+	case key == KeyBackspace && t.cursor > 0:
+		t.runes = append(t.runes[:t.cursor-1], t.runes[t.cursor:]...)
+		t.cursor--
+	case key == KeyDelete && t.cursor < len(t.runes):
+		t.runes = append(t.runes[:t.cursor], t.runes[t.cursor+1:]...)
+	case key == KeyLeft && t.cursor > 0:
+		t.cursor--
+	case key == KeyRight && t.cursor < len(t.runes):
+		t.cursor++
+	case key == KeyHome:
+		t.cursor = 0
+	case key == KeyEnd:
+		t.cursor = len(t.runes)
+	case (key == KeyEnter || key == KeyKPEnter) && action == KeyPressed:
+		if t.OnSubmit != nil {
+			t.OnSubmit(t.Text())
+		}
+	case shortcut && key == KeyV:
+		// a pasted line break would submit half the text, so keep it on one line
+		text := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(t.window.Clipboard())
+		t.insert(text)
+	case shortcut && key == KeyC && action == KeyPressed:
+		t.window.SetClipboard(t.Text())
+	case shortcut && key == KeyX && action == KeyPressed:
+		t.window.SetClipboard(t.Text())
+		t.Clear()
+	}
+}
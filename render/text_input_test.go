@@ -0,0 +1,56 @@
+package render
+
+import "testing"
+
+func TestRemoveHandlers(t *testing.T) {
+	in := NewInput()
+	var first, second int
+	removeFirst := in.OnKeyPress(func(Key) { first++ })
+	in.OnKeyPress(func(Key) { second++ })
+	removeChar := in.OnChar(func(rune) { first++ })
+	removeDrop := in.OnFileDrop(func([]string) { first++ })
+	removeResize := in.OnResize(func(int, int) { first++ })
+
+	in.Dispatch(KeyEvent{Key: KeyA, Action: KeyPressed})
+	removeFirst()
+	removeChar()
+	removeDrop()
+	removeResize()
+	removeFirst()
+	in.Dispatch(KeyEvent{Key: KeyB, Action: KeyPressed})
+	in.Dispatch(CharEvent{Char: 'b'})
+	in.Dispatch(FileDropEvent{Paths: []string{"a.png"}})
+	in.Dispatch(ResizeEvent{Width: 10, Height: 10})
+
+	if first != 1 || second != 2 {
+		t.Errorf("removed handler ran %d times, kept handler %d, want 1 and 2", first, second)
+	}
+}
+
+func TestTextInput(t *testing.T) {
+	w := &Window{Input: NewInput()}
+	ti := NewTextInput(w)
+	var submitted string
+	ti.OnSubmit = func(text string) { submitted = text }
+
+	for _, r := range "helo" {
+		w.Input.Dispatch(CharEvent{Char: r})
+	}
+	w.Input.Dispatch(KeyEvent{Key: KeyLeft, Action: KeyPressed})
+	w.Input.Dispatch(CharEvent{Char: 'l'})
+	w.Input.Dispatch(KeyEvent{Key: KeyEnd, Action: KeyPressed})
+	w.Input.Dispatch(KeyEvent{Key: KeyBackspace, Action: KeyRepeated})
+	w.Input.Dispatch(KeyEvent{Key: KeyEnter, Action: KeyPressed})
+
+	if ti.Text() != "hell" || ti.Cursor() != 4 || submitted != "hell" {
+		t.Errorf("text %q cursor %d submitted %q, want hell, 4, hell", ti.Text(), ti.Cursor(), submitted)
+	}
+
+	ti.Close()
+	ti.Close()
+	w.Input.Dispatch(CharEvent{Char: '!'})
+	w.Input.Dispatch(KeyEvent{Key: KeyHome, Action: KeyPressed})
+	if ti.Text() != "hell" || ti.Cursor() != 4 {
+		t.Errorf("closed input changed to %q cursor %d", ti.Text(), ti.Cursor())
+	}
+}
@@ -9,45 +9,155 @@ import (
 	"os"
 
 	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
 type Texture struct {
 	handle  uint32
 	target  uint32 // same target as gl.BindTexture(<this param>, ...)
 	texUnit uint32 // Texture unit that is currently bound to ex: gl.TEXTURE0
+	width   int32
+	height  int32
+	depth   int32 // layers of an array texture or slices of a 3D texture
 }
 
 var errUnsupportedStride = errors.New("unsupported stride, only 32-bit colors supported")
 
 var errTextureNotBound = errors.New("texture not bound")
 
-func NewTextureFromFile(file string) (*Texture, error) {
-	imgFile, err := os.Open(file)
-	if err != nil {
-		return nil, err
+// TextureOptions controls how a texture is sampled and stored. The zero value
+// gives clamped, linearly filtered, sRGB textures with no mipmaps.
+type TextureOptions struct {
+	WrapS int32 // gl.CLAMP_TO_EDGE, gl.REPEAT, gl.MIRRORED_REPEAT or gl.CLAMP_TO_BORDER
+	WrapT int32
+	WrapR int32 // only used by 3D and cube map textures
+
+	MinFilter int32 // gl.LINEAR, gl.NEAREST or one of the *_MIPMAP_* filters, which need GenerateMipmaps
+	MagFilter int32 // gl.LINEAR, or gl.NEAREST for pixel art
+
+	GenerateMipmaps bool
+	Anisotropy      float32 // values above 1 enable anisotropic filtering where supported, clamped to the driver max
+
+	Linear      bool // store the pixels as linear RGBA8 instead of sRGB
+	BorderColor [4]float32
+}
+
+// PixelArtTextureOptions returns options that keep hard pixel edges when scaled
+func PixelArtTextureOptions() TextureOptions {
+	return TextureOptions{MinFilter: gl.NEAREST, MagFilter: gl.NEAREST}
+}
+
+// MipmappedTextureOptions returns options for trilinear filtered, mipmapped textures
+func MipmappedTextureOptions() TextureOptions {
+	return TextureOptions{MinFilter: gl.LINEAR_MIPMAP_LINEAR, GenerateMipmaps: true}
+}
+
+func (o TextureOptions) withDefaults() TextureOptions {
+	return o.withMipLevels(o.GenerateMipmaps)
+}
+
+// withMipLevels fills in defaults for a texture that will or will not have a
+// mip chain, whether generated or uploaded from a file
+func (o TextureOptions) withMipLevels(mipmapped bool) TextureOptions {
+	if o.WrapS == 0 {
+		o.WrapS = gl.CLAMP_TO_EDGE
+	}
+	if o.WrapT == 0 {
+		o.WrapT = gl.CLAMP_TO_EDGE
+	}
+	if o.WrapR == 0 {
+		o.WrapR = gl.CLAMP_TO_EDGE
+	}
+	if o.MinFilter == 0 {
+		o.MinFilter = gl.LINEAR
+	}
+	if o.MagFilter == 0 {
+		o.MagFilter = gl.LINEAR
+	}
+	// a mipmap filter with only the base level leaves the texture incomplete,
+	// which samples as black, so fall back to the matching single level filter
+	if !mipmapped {
+		switch o.MinFilter {
+		case gl.LINEAR_MIPMAP_LINEAR, gl.LINEAR_MIPMAP_NEAREST:
+			o.MinFilter = gl.LINEAR
+		case gl.NEAREST_MIPMAP_LINEAR, gl.NEAREST_MIPMAP_NEAREST:
+			o.MinFilter = gl.NEAREST
+		}
+	}
+	return o
+}
+
+func (o TextureOptions) internalFormat() int32 {
+	if o.Linear {
+		return gl.RGBA8
+	}
+	return gl.SRGB8_ALPHA8
+}
+
+// apply sets the sampling parameters on the texture currently bound to target
+func (o TextureOptions) apply(target uint32) {
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_S, o.WrapS)
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_T, o.WrapT)
+	if target == gl.TEXTURE_3D || target == gl.TEXTURE_CUBE_MAP {
+		gl.TexParameteri(target, gl.TEXTURE_WRAP_R, o.WrapR)
+	}
+	gl.TexParameteri(target, gl.TEXTURE_MIN_FILTER, o.MinFilter) // minification filter
+	gl.TexParameteri(target, gl.TEXTURE_MAG_FILTER, o.MagFilter) // magnification filter
+	gl.TexParameterfv(target, gl.TEXTURE_BORDER_COLOR, &o.BorderColor[0])
+
+	if o.Anisotropy > 1 && anisotropySupported() {
+		var max float32
+		gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &max)
+		if o.Anisotropy < max {
+			max = o.Anisotropy
+		}
+		gl.TexParameterf(target, gl.TEXTURE_MAX_ANISOTROPY, max)
+	}
+}
+
+// anisotropySupported reports whether TEXTURE_MAX_ANISOTROPY can be set, which
+// needs the EXT extension, or the ARB one that made it core in 4.6. glfw is
+// asked rather than gl.EXTENSIONS, which core profile contexts do not have.
+func anisotropySupported() bool {
+	return glfw.ExtensionSupported("GL_EXT_texture_filter_anisotropic") ||
+		glfw.ExtensionSupported("GL_ARB_texture_filter_anisotropic")
+}
+
+// generateMipmaps builds the mip chain for the texture currently bound to target
+func (o TextureOptions) generateMipmaps(target uint32) {
+	if o.GenerateMipmaps {
+		gl.GenerateMipmap(target)
+	}
+}
+
+// NewTextureFromFile loads PNG and JPEG images, and KTX, KTX2 and DDS containers
+// by their file extension
+func NewTextureFromFile(file string, opts TextureOptions) (*Texture, error) {
+	if isCompressedFile(file) {
+		return NewCompressedTextureFromFile(file, opts)
 	}
-	defer imgFile.Close()
 
-	// Decode detexts the type of image as long as its image/<type> is imported
-	img, _, err := image.Decode(imgFile)
+	img, err := loadImageFile(file)
 	if err != nil {
 		return nil, err
 	}
-	return NewTexture(img)
+	return NewTexture(img, opts)
 }
 
-func NewTexture(img image.Image) (*Texture, error) {
-	rgba := image.NewRGBA(img.Bounds())
-	draw.Draw(rgba, rgba.Bounds(), img, image.Pt(0, 0), draw.Src)
-	if rgba.Stride != rgba.Rect.Size().X*4 { // TODO-cs: why?
-		return nil, errUnsupportedStride
+func NewTexture(img image.Image, opts TextureOptions) (*Texture, error) {
+	checkThread()
+	opts = opts.withDefaults()
+
+	rgba, err := toRGBA(img)
+	if err != nil {
+		return nil, err
 	}
 
 	var handle uint32
 	gl.GenTextures(1, &handle)
 
 	target := uint32(gl.TEXTURE_2D)
-	internalFmt := int32(gl.SRGB_ALPHA)
+	internalFmt := opts.internalFormat()
 	format := uint32(gl.RGBA)
 	width := int32(rgba.Rect.Size().X)
 	height := int32(rgba.Rect.Size().Y)
@@ -57,36 +167,65 @@ func NewTexture(img image.Image) (*Texture, error) {
 	texture := Texture{
 		handle: handle,
 		target: target,
+		width:  width,
+		height: height,
 	}
 
-	texture.Bind(gl.TEXTURE0)
+	texture.Bind(0)
 
 	// set the texture wrapping/filtering options (applies to current bound texture obj)
-	// TODO-cs
-	gl.TexParameteri(texture.target, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(texture.target, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(texture.target, gl.TEXTURE_MIN_FILTER, gl.LINEAR) // minification filter
-	gl.TexParameteri(texture.target, gl.TEXTURE_MAG_FILTER, gl.LINEAR) // magnification filter
+	opts.apply(texture.target)
 
 	gl.TexImage2D(target, 0, internalFmt, width, height, 0, format, pixType, dataPtr)
 
-	gl.GenerateMipmap(texture.handle)
+	opts.generateMipmaps(texture.target)
 
 	return &texture, nil
 }
 
+// toRGBA copies any image into tightly packed 8-bit RGBA rows ready for upload
+func toRGBA(img image.Image) (*image.RGBA, error) {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) && rgba.Stride == rgba.Rect.Dx()*4 {
+		return rgba, nil
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	if rgba.Stride != rgba.Rect.Size().X*4 { // TODO-cs: why?
+		return nil, errUnsupportedStride
+	}
+	return rgba, nil
+}
+
 func (tex *Texture) Bind(slot uint32) {
+	checkThread()
 	texUnit := gl.TEXTURE0 + slot
 	gl.ActiveTexture(gl.TEXTURE0 + slot)
 	gl.BindTexture(tex.target, tex.handle)
 	tex.texUnit = texUnit
 }
 
+func (tex *Texture) Width() int {
+	return int(tex.width)
+}
+
+func (tex *Texture) Height() int {
+	return int(tex.height)
+}
+
+func (tex *Texture) Depth() int {
+	return int(tex.depth)
+}
+
 func (tex *Texture) UnBind() {
 	tex.texUnit = 0
 	gl.BindTexture(tex.target, 0)
 }
 
+func (tex *Texture) Delete() {
+	gl.DeleteTextures(1, &tex.handle)
+	tex.handle = 0
+}
+
 func (tex *Texture) SetUniform(uniformLoc int32) error {
 	if tex.texUnit == 0 {
 		return errTextureNotBound
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+var errNoLayers = errors.New("layered texture needs at least one image")
+
+var errNotLayered = errors.New("texture is not an array or 3D texture")
+
+// NewTextureArray creates a TEXTURE_2D_ARRAY with one layer per image, sampled
+// in shaders through a sampler2DArray with the layer index as the third coordinate
+func NewTextureArray(layers []image.Image, opts TextureOptions) (*Texture, error) {
+	return newLayeredTexture(gl.TEXTURE_2D_ARRAY, layers, opts)
+}
+
+// NewTexture3D creates a TEXTURE_3D where each image is one slice of depth, such
+// as the blue slices of a color grading LUT
+func NewTexture3D(slices []image.Image, opts TextureOptions) (*Texture, error) {
+	return newLayeredTexture(gl.TEXTURE_3D, slices, opts)
+}
+
+func newLayeredTexture(target uint32, layers []image.Image, opts TextureOptions) (*Texture, error) {
+	if len(layers) == 0 {
+		return nil, errNoLayers
+	}
+	opts = opts.withDefaults()
+
+	size := layers[0].Bounds().Size()
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+
+	texture := Texture{
+		handle: handle,
+		target: target,
+		width:  int32(size.X),
+		height: int32(size.Y),
+		depth:  int32(len(layers)),
+	}
+	texture.Bind(0)
+
+	opts.apply(texture.target)
+
+	// allocate every layer up front then fill them in one at a time
+	gl.TexImage3D(target, 0, opts.internalFormat(), texture.width, texture.height, texture.depth, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	for i, img := range layers {
+		if err := texture.uploadLayer(i, img); err != nil {
+			gl.DeleteTextures(1, &handle)
+			return nil, err
+		}
+	}
+
+	opts.generateMipmaps(texture.target)
+
+	return &texture, nil
+}
+
+// SetLayer replaces one layer of an array texture or one slice of a 3D texture.
+// Mipmaps are not regenerated.
+func (tex *Texture) SetLayer(layer int, img image.Image) error {
+	if tex.target != gl.TEXTURE_2D_ARRAY && tex.target != gl.TEXTURE_3D {
+		return errNotLayered
+	}
+	tex.Bind(0)
+	return tex.uploadLayer(layer, img)
+}
+
+func (tex *Texture) uploadLayer(layer int, img image.Image) error {
+	if layer < 0 || layer >= int(tex.depth) {
+		return fmt.Errorf("layer %d out of range, texture has %d", layer, tex.depth)
+	}
+	if size := img.Bounds().Size(); size.X != int(tex.width) || size.Y != int(tex.height) {
+		return fmt.Errorf("layer %d is %v, expected %dx%d", layer, size, tex.width, tex.height)
+	}
+	rgba, err := toRGBA(img)
+	if err != nil {
+		return err
+	}
+	gl.TexSubImage3D(tex.target, 0, 0, 0, int32(layer), tex.width, tex.height, 1, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	return nil
+}
+
+// SplitLUTStrip cuts a horizontal strip LUT, n slices of n x n pixels laid side
+// by side, into the slices NewTexture3D expects
+func SplitLUTStrip(img image.Image) ([]image.Image, error) {
+	b := img.Bounds()
+	n := b.Dy()
+	if n == 0 || b.Dx() != n*n {
+		return nil, fmt.Errorf("a %dx%d image is not an n*n by n LUT strip", b.Dx(), b.Dy())
+	}
+
+	slices := make([]image.Image, n)
+	for i := range slices {
+		slice := image.NewRGBA(image.Rect(0, 0, n, n))
+		draw.Draw(slice, slice.Bounds(), img, b.Min.Add(image.Pt(i*n, 0)), draw.Src)
+		slices[i] = slice
+	}
+	return slices, nil
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kevholditch/opengl-playground/atlas"
+)
+
+type TextureAtlas struct {
+	*Texture
+	Regions map[string]atlas.Region
+}
+
+func NewTextureAtlas(a *atlas.Atlas, opts TextureOptions) (*TextureAtlas, error) {
+	tex, err := NewTexture(a.Image, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TextureAtlas{Texture: tex, Regions: a.Regions}, nil
+}
+
+// NewTextureAtlasFromFile loads a manifest written by atlaspack along with the
+// atlas image it points at, which is resolved relative to the manifest
+func NewTextureAtlasFromFile(manifestFile string, opts TextureOptions) (*TextureAtlas, error) {
+	f, err := os.Open(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := atlas.ReadManifest(f)
+	if err != nil {
+		return nil, err
+	}
+
+	tex, err := NewTextureFromFile(filepath.Join(filepath.Dir(manifestFile), m.Image), opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TextureAtlas{Texture: tex, Regions: m.Regions}, nil
+}
+
+func (ta *TextureAtlas) Region(name string) (atlas.Region, error) {
+	r, ok := ta.Regions[name]
+	if !ok {
+		return atlas.Region{}, fmt.Errorf("atlas has no region named %s", name)
+	}
+	return r, nil
+}
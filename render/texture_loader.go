@@ -0,0 +1,178 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"time"
+
+	"github.com/kevholditch/opengl-playground/compressed"
+)
+
+// AsyncTexture stands in for a texture that is still loading. Until the upload
+// completes it binds the loader's placeholder instead.
+type AsyncTexture struct {
+	File        string
+	placeholder *Texture
+	texture     *Texture
+	err         error
+	done        bool
+}
+
+// Texture returns the loaded texture, or the placeholder if it is not ready or
+// failed to load
+func (t *AsyncTexture) Texture() *Texture {
+	if t.texture != nil {
+		return t.texture
+	}
+	return t.placeholder
+}
+
+func (t *AsyncTexture) Bind(slot uint32) {
+	t.Texture().Bind(slot)
+}
+
+func (t *AsyncTexture) Ready() bool {
+	return t.texture != nil
+}
+
+// Done reports whether loading has finished, successfully or not
+func (t *AsyncTexture) Done() bool {
+	return t.done
+}
+
+func (t *AsyncTexture) Err() error {
+	return t.err
+}
+
+type decoded struct {
+	target *AsyncTexture
+	upload func() (*Texture, error)
+	err    error
+}
+
+// TextureLoader decodes image files on worker goroutines and uploads them on
+// the GL thread from Update. Load, Update and the AsyncTexture methods must
+// all be called from the GL thread.
+type TextureLoader struct {
+	opts        TextureOptions
+	placeholder *Texture
+	workers     chan struct{}
+	decoded     chan decoded
+	queued      []decoded
+	pending     int
+}
+
+func NewTextureLoader(workers int, opts TextureOptions) (*TextureLoader, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	placeholder, err := NewTexture(checkerboard(), PixelArtTextureOptions())
+	if err != nil {
+		return nil, err
+	}
+	return &TextureLoader{
+		opts:        opts,
+		placeholder: placeholder,
+		workers:     make(chan struct{}, workers),
+		decoded:     make(chan decoded, workers),
+	}, nil
+}
+
+// checkerboard is the magenta and black placeholder shown while loading
+func checkerboard() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c := color.RGBA{A: 255}
+			if (x/4+y/4)%2 == 0 {
+				c = color.RGBA{R: 255, B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// Load starts decoding file in the background and returns straight away
+func (l *TextureLoader) Load(file string) *AsyncTexture {
+	t := &AsyncTexture{File: file, placeholder: l.placeholder}
+	l.pending++
+
+	go func() {
+		l.workers <- struct{}{}
+		upload, err := l.decode(file)
+		<-l.workers
+		l.decoded <- decoded{target: t, upload: upload, err: err}
+	}()
+	return t
+}
+
+// decode does the file reading and pixel conversion off the GL thread and
+// returns the GL work that is left
+func (l *TextureLoader) decode(file string) (func() (*Texture, error), error) {
+	opts := l.opts
+	if isCompressedFile(file) {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		ct, err := compressed.Decode(f)
+		if err != nil {
+			return nil, err
+		}
+		return func() (*Texture, error) { return NewCompressedTexture(ct, opts) }, nil
+	}
+
+	img, err := loadImageFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rgba, err := toRGBA(img)
+	if err != nil {
+		return nil, err
+	}
+	return func() (*Texture, error) { return NewTexture(rgba, opts) }, nil
+}
+
+// Update uploads decoded textures until budget is used up, leaving the rest
+// for the next frame, and returns how many finished. A budget of zero uploads
+// everything that is ready.
+func (l *TextureLoader) Update(budget time.Duration) int {
+	start := time.Now()
+	finished := 0
+
+drain:
+	for {
+		select {
+		case d := <-l.decoded:
+			l.queued = append(l.queued, d)
+		default:
+			break drain
+		}
+	}
+
+	for len(l.queued) > 0 {
+		if budget > 0 && finished > 0 && time.Since(start) >= budget {
+			break
+		}
+		d := l.queued[0]
+		l.queued = l.queued[1:]
+
+		if d.err == nil {
+			d.target.texture, d.target.err = d.upload()
+		} else {
+			d.target.err = d.err
+		}
+		d.target.done = true
+		l.pending--
+		finished++
+	}
+	return finished
+}
+
+// Pending is the number of textures still decoding or waiting to upload
+func (l *TextureLoader) Pending() int {
+	return l.pending
+}
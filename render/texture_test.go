@@ -0,0 +1,39 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+func TestTextureOptionsMinFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TextureOptions
+		want int32
+	}{
+		{"default", TextureOptions{}, gl.LINEAR},
+		{"pixel art", PixelArtTextureOptions(), gl.NEAREST},
+		{"mipmapped", MipmappedTextureOptions(), gl.LINEAR_MIPMAP_LINEAR},
+		{"linear mipmap without mipmaps", TextureOptions{MinFilter: gl.LINEAR_MIPMAP_NEAREST}, gl.LINEAR},
+		{"nearest mipmap without mipmaps", TextureOptions{MinFilter: gl.NEAREST_MIPMAP_LINEAR}, gl.NEAREST},
+		{"nearest mipmap with mipmaps", TextureOptions{MinFilter: gl.NEAREST_MIPMAP_NEAREST, GenerateMipmaps: true}, gl.NEAREST_MIPMAP_NEAREST},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.withDefaults().MinFilter; got != tt.want {
+				t.Errorf("MinFilter = 0x%x, want 0x%x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextureOptionsUploadedMipLevels(t *testing.T) {
+	opts := TextureOptions{MinFilter: gl.LINEAR_MIPMAP_LINEAR}
+	if got := opts.withMipLevels(true); got.MinFilter != gl.LINEAR_MIPMAP_LINEAR || got.GenerateMipmaps {
+		t.Errorf("uploaded levels gave MinFilter 0x%x GenerateMipmaps %v", got.MinFilter, got.GenerateMipmaps)
+	}
+	if got := opts.withMipLevels(false); got.MinFilter != gl.LINEAR {
+		t.Errorf("a single level kept MinFilter 0x%x", got.MinFilter)
+	}
+}
@@ -0,0 +1,304 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML reads the subset of TOML that config files need: key/value
+// pairs, [table] headers, strings, numbers, booleans, arrays and inline
+// tables. Dates and multi-line strings are not supported.
+func parseTOML(src string) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	table := root
+
+	for n, line := range strings.Split(src, "\n") {
+		p := &tomlParser{src: line, line: n + 1}
+		p.skipSpace()
+		if p.done() {
+			continue
+		}
+
+		if p.peek() == '[' {
+			p.pos++
+			name, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume(']') {
+				return nil, p.errorf("expected ] after table name")
+			}
+			if table, err = subTable(root, name); err != nil {
+				return nil, p.errorf("%v", err)
+			}
+		} else {
+			name, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume('=') {
+				return nil, p.errorf("expected = after %s", strings.Join(name, "."))
+			}
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			parent, err := subTable(table, name[:len(name)-1])
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			parent[name[len(name)-1]] = value
+		}
+
+		p.skipSpace()
+		if !p.done() {
+			return nil, p.errorf("unexpected %q", p.src[p.pos:])
+		}
+	}
+	return root, nil
+}
+
+func subTable(t map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for _, name := range path {
+		next, ok := t[name]
+		if !ok {
+			next = map[string]interface{}{}
+			t[name] = next
+		}
+		sub, ok := next.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not a table", name)
+		}
+		t = sub
+	}
+	return t, nil
+}
+
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// done reports whether the rest of the line is empty or a comment
+func (p *tomlParser) done() bool {
+	return p.pos >= len(p.src) || p.src[p.pos] == '#'
+}
+
+func (p *tomlParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.peek() != c {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+// key reads a bare, quoted or dotted key
+func (p *tomlParser) key() ([]string, error) {
+	var parts []string
+	for {
+		p.skipSpace()
+		var part string
+		switch p.peek() {
+		case '"', '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && isBareKeyChar(p.src[p.pos]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected a key")
+			}
+			part = p.src[start:p.pos]
+		}
+		parts = append(parts, part)
+		if !p.consume('.') {
+			return parts, nil
+		}
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (interface{}, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r,]}#", p.src[p.pos]) < 0 {
+		p.pos++
+	}
+	word := p.src[start:p.pos]
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "":
+		return nil, p.errorf("expected a value")
+	}
+
+	if n, ok := tomlNumber(word); ok {
+		return n, nil
+	}
+	return nil, p.errorf("invalid value %q", word)
+}
+
+// tomlNumber parses an integer as int64 or a float as float64. Unlike Go a
+// leading zero does not mean octal: only the 0x, 0o and 0b prefixes pick
+// another base, and decimals may not start with a zero.
+func tomlNumber(word string) (interface{}, bool) {
+	number := strings.Replace(word, "_", "", -1)
+	if len(number) > 2 && number[0] == '0' {
+		base := 0
+		switch number[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			digits := number[2:]
+			if digits[0] == '+' || digits[0] == '-' {
+				return nil, false
+			}
+			i, err := strconv.ParseInt(digits, base, 64)
+			return i, err == nil
+		}
+	}
+
+	unsigned := strings.TrimLeft(number, "+-")
+	if len(number)-len(unsigned) > 1 {
+		return nil, false
+	}
+	if len(unsigned) > 1 && unsigned[0] == '0' && unsigned[1] >= '0' && unsigned[1] <= '9' {
+		return nil, false
+	}
+	if i, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return i, true
+	}
+
+	// ParseFloat also takes hex floats and Infinity, which TOML does not
+	if unsigned != "inf" && unsigned != "nan" && strings.Trim(unsigned, "0123456789.eE+-") != "" {
+		return nil, false
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	return f, err == nil
+}
+
+func (p *tomlParser) str() (string, error) {
+	quote := p.src[p.pos]
+	end := p.pos + 1
+	for ; end < len(p.src); end++ {
+		if p.src[end] == '\\' && quote == '"' {
+			end++
+			continue
+		}
+		if p.src[end] == quote {
+			break
+		}
+	}
+	if end >= len(p.src) {
+		return "", p.errorf("unterminated string")
+	}
+	raw := p.src[p.pos : end+1]
+	p.pos = end + 1
+
+	// literal strings have no escapes, basic strings escape like Go
+	if quote == '\'' {
+		return raw[1 : len(raw)-1], nil
+	}
+	s, err := strconv.Unquote(raw)
+	if err != nil {
+		return "", p.errorf("invalid string %s", raw)
+	}
+	return s, nil
+}
+
+func (p *tomlParser) array() ([]interface{}, error) {
+	p.pos++
+	values := []interface{}{}
+	for {
+		if p.consume(']') {
+			return values, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if !p.consume(',') {
+			if !p.consume(']') {
+				return nil, p.errorf("expected , or ] in array")
+			}
+			return values, nil
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (map[string]interface{}, error) {
+	p.pos++
+	t := map[string]interface{}{}
+	if p.consume('}') {
+		return t, nil
+	}
+	for {
+		name, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume('=') {
+			return nil, p.errorf("expected = in inline table")
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		parent, err := subTable(t, name[:len(name)-1])
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		parent[name[len(name)-1]] = v
+		if !p.consume(',') {
+			if !p.consume('}') {
+				return nil, p.errorf("expected , or } in inline table")
+			}
+			return t, nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTOMLNumber(t *testing.T) {
+	tests := []struct {
+		word string
+		want interface{}
+	}{
+		{"0", int64(0)},
+		{"42", int64(42)},
+		{"+17", int64(17)},
+		{"-5", int64(-5)},
+		{"1_000", int64(1000)},
+		{"0xff", int64(255)},
+		{"0xDEAD_beef", int64(0xdeadbeef)},
+		{"0o17", int64(15)},
+		{"0b101", int64(5)},
+		{"1.5", 1.5},
+		{"-0.25", -0.25},
+		{"6e2", 600.0},
+		{"1e-2", 0.01},
+		{"0.5", 0.5},
+		{"+inf", math.Inf(1)},
+		{"-inf", math.Inf(-1)},
+
+		// rejected
+		{"010", nil},
+		{"-007", nil},
+		{"0X10", nil},
+		{"0O17", nil},
+		{"0x-1", nil},
+		{"0x", nil},
+		{"0b102", nil},
+		{"0x1p4", nil},
+		{"Infinity", nil},
+		{"01.5", nil},
+		{"--1", nil},
+		{"abc", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			got, ok := tomlNumber(tt.word)
+			if tt.want == nil {
+				if ok {
+					t.Errorf("got %v (%T), want an error", got, got)
+				}
+				return
+			}
+			if !ok || got != tt.want {
+				t.Errorf("got %v (%T) ok %v, want %v (%T)", got, got, ok, tt.want, tt.want)
+			}
+		})
+	}
+
+	if f, ok := tomlNumber("nan"); !ok || !math.IsNaN(f.(float64)) {
+		t.Errorf("nan parsed as %v, %v", f, ok)
+	}
+}
+
+func TestParseTOMLIntegers(t *testing.T) {
+	got, err := parseTOML("[window]\nwidth = 0x400\nheight = 768\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	window := got["window"].(map[string]interface{})
+	if window["width"] != int64(1024) || window["height"] != int64(768) {
+		t.Errorf("got %v", window)
+	}
+	if _, err := parseTOML("width = 010\n"); err == nil {
+		t.Error("a leading zero was accepted")
+	}
+}
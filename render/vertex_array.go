@@ -9,6 +9,7 @@ type VertexArray struct {
 }
 
 func NewVertexArray() *VertexArray {
+	checkThread()
 	var vao uint32
 	gl.GenVertexArrays(1, &vao)
 	gl.BindVertexArray(vao)
@@ -29,6 +30,7 @@ func (v *VertexArray) AddBuffer(vb *VertexBuffer, layout *VertexBufferLayout) {
 }
 
 func (v *VertexArray) Bind() {
+	checkThread()
 	gl.BindVertexArray(v.handle)
 }
 
@@ -9,6 +9,7 @@ type VertexBuffer struct {
 }
 
 func NewVertexBuffer(values []float32) *VertexBuffer {
+	checkThread()
 
 	var buffer uint32
 	gl.GenBuffers(1, &buffer)
@@ -18,7 +19,28 @@ func NewVertexBuffer(values []float32) *VertexBuffer {
 	return &VertexBuffer{handle: buffer}
 }
 
+// NewDynamicVertexBuffer allocates room for floatCount floats that are filled in
+// later with SetData, for geometry that changes every frame
+func NewDynamicVertexBuffer(floatCount int) *VertexBuffer {
+	checkThread()
+	var buffer uint32
+	gl.GenBuffers(1, &buffer)
+	gl.BindBuffer(gl.ARRAY_BUFFER, buffer)
+	gl.BufferData(gl.ARRAY_BUFFER, floatCount*sizeOfFloat32, nil, gl.DYNAMIC_DRAW)
+
+	return &VertexBuffer{handle: buffer}
+}
+
+func (v *VertexBuffer) SetData(values []float32) {
+	if len(values) == 0 {
+		return
+	}
+	v.Bind()
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(values)*sizeOfFloat32, gl.Ptr(values))
+}
+
 func (v *VertexBuffer) Bind() {
+	checkThread()
 	gl.BindBuffer(gl.ARRAY_BUFFER, v.handle)
 }
 
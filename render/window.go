@@ -1,51 +1,182 @@
 package render
 
 import (
-	"fmt"
+	"image"
+
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
-type Config struct {
-	MajorVersion int
-	MinorVersion int
-	Width        int
-	Height       int
-	Title        string
-	SwapInterval int
-}
-
 type Window struct {
-	handle *glfw.Window
+	*Input
+	handle  *glfw.Window
+	pads    *gamepadState
+	actions *ActionMap
+
+	scaleHandlers []func(x, y float32)
+
+	frame       int
+	time, delta float64
+	lastSwap    float64
+	recording   *InputRecording
+	recordStart int
+	playback    *inputPlayback
+
+	cfg        Config
+	fullscreen bool
+	windowed   struct{ x, y, width, height int }
+
+	screenshotDir     string
+	screenshotPending bool
+	screenshotOpts    ScreenshotOptions
+	pixelReader       *PixelReader
 }
 
 func NewWindow(cfg Config) (*Window, error) {
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	if cfg.Icon == nil && cfg.IconFile != "" {
+		icon, err := loadImageFile(cfg.IconFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Icon = icon
+	}
+
+	// start from the defaults so settings don't leak from a previous window
+	glfw.DefaultWindowHints()
+	glfw.WindowHint(glfw.Resizable, glfwBool(cfg.Resizable))
+	glfw.WindowHint(glfw.ScaleToMonitor, glfwBool(cfg.ScaleToMonitor))
 	glfw.WindowHint(glfw.ContextVersionMajor, cfg.MajorVersion)
 	glfw.WindowHint(glfw.ContextVersionMinor, cfg.MinorVersion)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, gl.TRUE)
+	glfw.WindowHint(glfw.OpenGLDebugContext, glfwBool(cfg.Debug))
+	glfw.WindowHint(glfw.Samples, cfg.Samples)
+	if cfg.DepthBits > 0 {
+		glfw.WindowHint(glfw.DepthBits, cfg.DepthBits)
+	}
+	if cfg.StencilBits > 0 {
+		glfw.WindowHint(glfw.StencilBits, cfg.StencilBits)
+	}
+	glfw.WindowHint(glfw.SRGBCapable, glfwBool(cfg.SRGB))
+	glfw.WindowHint(glfw.Decorated, glfwBool(!cfg.Undecorated))
+	glfw.WindowHint(glfw.Floating, glfwBool(cfg.Floating))
+	glfw.WindowHint(glfw.TransparentFramebuffer, glfwBool(cfg.Transparent))
+	// glfw 3.3 has no position hint, so open hidden and show once moved
+	if cfg.Position != nil {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+	}
+
+	var monitor *glfw.Monitor
+	width, height := cfg.Width, cfg.Height
+	var cover *glfw.Monitor
+	switch {
+	case cfg.Borderless:
+		m, err := monitorAt(cfg.Monitor)
+		if err != nil {
+			return nil, err
+		}
+		cover = m
+		mode := m.GetVideoMode()
+		width, height = mode.Width, mode.Height
+		glfw.WindowHint(glfw.Decorated, glfw.False)
+		glfw.WindowHint(glfw.Visible, glfw.False)
+	case cfg.Fullscreen:
+		m, err := monitorAt(cfg.Monitor)
+		if err != nil {
+			return nil, err
+		}
+		monitor = m
+		var refresh int
+		width, height, refresh = fullscreenMode(m, cfg.Width, cfg.Height, cfg.RefreshRate)
+		glfw.WindowHint(glfw.RefreshRate, refresh)
+	}
+
+	var share *glfw.Window
+	if cfg.Share != nil {
+		share = cfg.Share.handle
+	}
 
-	window, err := glfw.CreateWindow(cfg.Width, cfg.Height, cfg.Title, nil, nil)
+	window, err := glfw.CreateWindow(width, height, cfg.Title, monitor, share)
 	if err != nil {
 		return nil, err
 	}
 	window.MakeContextCurrent()
 
-	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		fmt.Printf("%d\n", key)
+	glfw.SwapInterval(cfg.SwapInterval)
+	if cfg.SRGB {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+	if cfg.Samples > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+	}
 
-	})
+	switch {
+	case cover != nil:
+		window.SetPos(cover.GetPos())
+		window.Show()
+	case cfg.Position != nil && monitor == nil:
+		window.SetPos(cfg.Position.X, cfg.Position.Y)
+		window.Show()
+	case cfg.Position != nil:
+		window.Show()
+	}
+	if cfg.Icon != nil {
+		window.SetIcon([]image.Image{cfg.Icon})
+	}
+	if cfg.Cursor != nil {
+		window.SetCursor(cfg.Cursor.handle)
+	}
 
-	glfw.SwapInterval(cfg.SwapInterval)
+	w := &Window{Input: NewInput(), handle: window, pads: newGamepadState(), cfg: cfg}
+	// leaving fullscreen restores this size, centred on the same monitor
+	w.windowed.width, w.windowed.height = cfg.Width, cfg.Height
+	if w.windowed.width == 0 || w.windowed.height == 0 {
+		w.windowed.width, w.windowed.height = width/2, height/2
+	}
+	if cover == nil {
+		cover = monitor
+	}
+	if cover != nil {
+		w.fullscreen = true
+		mx, my := cover.GetPos()
+		w.windowed.x = mx + (width-w.windowed.width)/2
+		w.windowed.y = my + (height-w.windowed.height)/2
+		if cfg.Position != nil {
+			w.windowed.x, w.windowed.y = cfg.Position.X, cfg.Position.Y
+		}
+	}
+	w.lastSwap = glfw.GetTime()
+	w.installEventCallbacks()
+	w.installMouseCallbacks()
+	w.installResizeCallbacks()
+
+	return w, nil
+}
+
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+	return glfw.False
+}
 
-	return &Window{handle: window}, nil
+// OnKey registers a handler for every key press, repeat and release. Calling
+// the returned function removes the handler again.
+func (in *Input) OnKey(handler func(key Key, action KeyAction, mods Modifier)) (remove func()) {
+	in.keyHandlers = append(in.keyHandlers, handler)
+	i := len(in.keyHandlers) - 1
+	// the slot is cleared rather than cut out so other handlers keep their index
+	return func() { in.keyHandlers[i] = nil }
 }
 
-func (w *Window) OnKeyPress(onPressFunc func(key int)) {
-	w.handle.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		onPressFunc(int(key))
+// OnKeyPress registers a handler called when a key first goes down, returning
+// a function that removes it
+func (in *Input) OnKeyPress(onPressFunc func(key Key)) (remove func()) {
+	return in.OnKey(func(key Key, action KeyAction, mods Modifier) {
+		if action == KeyPressed {
+			onPressFunc(key)
+		}
 	})
 }
 
@@ -54,5 +185,39 @@ func (w *Window) ShouldClose() bool {
 }
 
 func (w *Window) SwapBuffers() {
+	checkThread()
+	w.updateScreenshots()
+	w.Input.EndFrame()
+	w.pads.endFrame()
+	w.advanceFrame()
 	w.handle.SwapBuffers()
 }
+
+func (w *Window) advanceFrame() {
+	w.frame++
+	now := glfw.GetTime()
+	if w.playback != nil {
+		w.delta = w.playbackStep()
+	} else {
+		w.delta = now - w.lastSwap
+	}
+	w.lastSwap = now
+	w.time += w.delta
+	w.advancePlayback()
+}
+
+// Frame counts calls to SwapBuffers
+func (w *Window) Frame() int {
+	return w.frame
+}
+
+// DeltaTime is the time in seconds the last frame took, or the fixed step
+// while playing back recorded input
+func (w *Window) DeltaTime() float64 {
+	return w.delta
+}
+
+// Time is the total of every frame's DeltaTime
+func (w *Window) Time() float64 {
+	return w.time
+}
@@ -0,0 +1,90 @@
+package render
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// MakeCurrent makes the window's context the target of GL calls on this thread
+func (w *Window) MakeCurrent() {
+	checkThread()
+	w.handle.MakeContextCurrent()
+}
+
+// Close asks the window to close, as if the user had clicked its close button
+func (w *Window) Close() {
+	w.handle.SetShouldClose(true)
+}
+
+// Destroy closes the window and frees its context
+func (w *Window) Destroy() {
+	checkThread()
+	w.handle.Destroy()
+}
+
+// withContext runs fn with the window's context current, for callbacks that
+// arrive while another window is being drawn
+func (w *Window) withContext(fn func()) {
+	current := glfw.GetCurrentContext()
+	if current != w.handle {
+		w.handle.MakeContextCurrent()
+		defer func() {
+			if current != nil {
+				current.MakeContextCurrent()
+			}
+		}()
+	}
+	fn()
+}
+
+type loopWindow struct {
+	window *Window
+	draw   func()
+}
+
+// WindowLoop drives several windows from one loop. Windows created with
+// Config.Share share textures, buffers and shader programs with that window,
+// but vertex arrays and framebuffers belong to one context so each window
+// must create its own.
+type WindowLoop struct {
+	windows []loopWindow
+}
+
+func NewWindowLoop() *WindowLoop {
+	return &WindowLoop{}
+}
+
+// Add registers a window and the function that draws it. The first window
+// added is the main one, the loop ends when it closes. Give extra windows a
+// SwapInterval of 0, otherwise each swap waits for vsync in turn.
+func (l *WindowLoop) Add(w *Window, draw func()) *WindowLoop {
+	l.windows = append(l.windows, loopWindow{window: w, draw: draw})
+	return l
+}
+
+// Run draws and swaps each window in turn then polls events, until the main
+// window closes. Other windows are destroyed as they are closed.
+func (l *WindowLoop) Run() {
+	for len(l.windows) > 0 && !l.windows[0].window.ShouldClose() {
+		l.Step()
+	}
+}
+
+// Step draws and swaps every open window once then polls for events
+func (l *WindowLoop) Step() {
+	open := l.windows[:0]
+	for i, lw := range l.windows {
+		if i > 0 && lw.window.ShouldClose() {
+			lw.window.Destroy()
+			continue
+		}
+		lw.window.MakeCurrent()
+		lw.draw()
+		lw.window.SwapBuffers()
+		open = append(open, lw)
+	}
+	l.windows = open
+
+	// leave the main window current for code that runs between steps
+	if len(l.windows) > 0 {
+		l.windows[0].window.MakeCurrent()
+	}
+	glfw.PollEvents()
+}
@@ -0,0 +1,129 @@
+package sdf
+
+import (
+	"image"
+	"math"
+)
+
+const inf = 1e20
+
+// Threshold is the mask alpha at or above which a pixel counts as inside the shape
+const Threshold = 128
+
+// Generate turns a binary mask into a signed distance field of the same size.
+// 128 sits on the shape's edge, higher values are inside and lower values are
+// outside; spread is the distance in pixels mapped onto the full 0-255 range
+// either side of the edge.
+func Generate(mask *image.Alpha, spread int) *image.Alpha {
+	b := mask.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if spread < 1 {
+		spread = 1
+	}
+
+	// squared distance from each pixel to the nearest inside and outside pixel
+	toInside := make([]float64, w*h)
+	toOutside := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if mask.AlphaAt(b.Min.X+x, b.Min.Y+y).A >= Threshold {
+				toOutside[i] = inf
+			} else {
+				toInside[i] = inf
+			}
+		}
+	}
+	edt2d(toInside, w, h)
+	edt2d(toOutside, w, h)
+
+	out := image.NewAlpha(image.Rect(0, 0, w, h))
+	for i := range toInside {
+		var d float64
+		if toInside[i] > 0 {
+			d = math.Sqrt(toInside[i]) - 0.5
+		} else {
+			d = -(math.Sqrt(toOutside[i]) - 0.5)
+		}
+		v := 0.5 - d/(2*float64(spread))
+		out.Pix[i] = uint8(math.Round(clamp(v, 0, 1) * 255))
+	}
+	return out
+}
+
+// FromImage builds a mask from the alpha channel of any image
+func FromImage(img image.Image) *image.Alpha {
+	b := img.Bounds()
+	mask := image.NewAlpha(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			_, _, _, a := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			mask.Pix[y*mask.Stride+x] = uint8(a >> 8)
+		}
+	}
+	return mask
+}
+
+// edt2d replaces grid, holding 0 for feature pixels and inf elsewhere, with the
+// squared euclidean distance to the nearest feature (Felzenszwalb & Huttenlocher)
+func edt2d(grid []float64, w, h int) {
+	n := w
+	if h > n {
+		n = h
+	}
+	f := make([]float64, n)
+	d := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			f[y] = grid[y*w+x]
+		}
+		edt1d(f, d, v, z, h)
+		for y := 0; y < h; y++ {
+			grid[y*w+x] = d[y]
+		}
+	}
+	for y := 0; y < h; y++ {
+		copy(f, grid[y*w:(y+1)*w])
+		edt1d(f, d, v, z, w)
+		copy(grid[y*w:(y+1)*w], d[:w])
+	}
+}
+
+func edt1d(f, d []float64, v []int, z []float64, n int) {
+	k := 0
+	v[0] = 0
+	z[0] = -inf
+	z[1] = inf
+	for q := 1; q < n; q++ {
+		s := intersect(f, q, v[k])
+		for s <= z[k] {
+			k--
+			s = intersect(f, q, v[k])
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = inf
+	}
+
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		dq := float64(q - v[k])
+		d[q] = dq*dq + f[v[k]]
+	}
+}
+
+func intersect(f []float64, q, p int) float64 {
+	fq, fp := f[q]+float64(q*q), f[p]+float64(p*p)
+	return (fq - fp) / float64(2*q-2*p)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
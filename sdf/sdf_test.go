@@ -0,0 +1,108 @@
+package sdf
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// halfPlane is a w by h mask with the left inside columns set
+func halfPlane(w, h, inside int) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < inside; x++ {
+			mask.SetAlpha(x, y, color.Alpha{A: 255})
+		}
+	}
+	return mask
+}
+
+func TestGenerateEdge(t *testing.T) {
+	// with a spread of 4 each pixel step away from the edge moves the value by 255/8
+	want := []uint8{239, 207, 175, 143, 112, 80, 48, 16}
+	out := Generate(halfPlane(len(want), 3, 4), 4)
+	for y := 0; y < 3; y++ {
+		for x, w := range want {
+			if got := out.AlphaAt(x, y).A; got != w {
+				t.Errorf("pixel %d,%d = %d, want %d", x, y, got, w)
+			}
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mask   *image.Alpha
+		spread int
+		x, y   int
+		want   uint8
+	}{
+		{"empty mask is all outside", image.NewAlpha(image.Rect(0, 0, 4, 4)), 2, 1, 1, 0},
+		{"full mask is all inside", halfPlane(4, 4, 4), 2, 1, 1, 255},
+		{"spread below one is treated as one", halfPlane(4, 1, 2), 0, 1, 0, 191},
+		{"threshold counts as inside", func() *image.Alpha {
+			m := image.NewAlpha(image.Rect(0, 0, 2, 1))
+			m.SetAlpha(0, 0, color.Alpha{A: Threshold})
+			return m
+		}(), 1, 0, 0, 191},
+		{"offset bounds", halfPlane(4, 4, 2).SubImage(image.Rect(1, 1, 4, 4)).(*image.Alpha), 4, 0, 0, 143},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := Generate(tt.mask, tt.spread)
+			if out.Rect != image.Rect(0, 0, tt.mask.Rect.Dx(), tt.mask.Rect.Dy()) {
+				t.Errorf("output bounds %v do not match the mask", out.Rect)
+			}
+			if got := out.AlphaAt(tt.x, tt.y).A; got != tt.want {
+				t.Errorf("pixel %d,%d = %d, want %d", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEDTMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const w, h = 13, 9
+	grid := make([]float64, w*h)
+	var features []image.Point
+	for i := range grid {
+		if r.Intn(6) == 0 {
+			features = append(features, image.Pt(i%w, i/w))
+		} else {
+			grid[i] = inf
+		}
+	}
+	edt2d(grid, w, h)
+
+	for i, got := range grid {
+		x, y := i%w, i/w
+		want := inf
+		for _, f := range features {
+			dx, dy := float64(x-f.X), float64(y-f.Y)
+			if d := dx*dx + dy*dy; d < want {
+				want = d
+			}
+		}
+		if got != want {
+			t.Errorf("pixel %d,%d = %v, want %v", x, y, got, want)
+		}
+	}
+}
+
+func TestFromImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(2, 3, 5, 4))
+	img.Set(2, 3, color.NRGBA{R: 255, A: 255})
+	img.Set(3, 3, color.NRGBA{G: 255, A: 100})
+
+	mask := FromImage(img)
+	if mask.Rect != image.Rect(0, 0, 3, 1) {
+		t.Fatalf("mask bounds %v, want 3x1 at the origin", mask.Rect)
+	}
+	for x, want := range []uint8{255, 100, 0} {
+		if got := mask.AlphaAt(x, 0).A; got != want {
+			t.Errorf("alpha at %d = %d, want %d", x, got, want)
+		}
+	}
+}
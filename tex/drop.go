@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/kevholditch/opengl-playground/render"
+)
+
+// loadDroppedProgram links a program from a dropped .shader file. A file with
+// #shader sections is a whole program, otherwise it replaces the stage its
+// name suggests and is linked with the current file for the other stage.
+func loadDroppedProgram(path string, vertexFile, fragmentFile *string) (*render.Program, error) {
+	combined, err := render.IsCombinedShaderFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if combined {
+		return render.NewProgramFromFile(path)
+	}
+
+	vertex, fragment := *vertexFile, *fragmentFile
+	if strings.Contains(strings.ToLower(filepath.Base(path)), "frag") {
+		fragment = path
+	} else {
+		vertex = path
+	}
+
+	vs, err := render.NewShaderFromFile(vertex, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := render.NewShaderFromFile(fragment, gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vs.Handle)
+		return nil, err
+	}
+	program, err := render.NewProgram(vs, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	*vertexFile, *fragmentFile = vertex, fragment
+	return program, nil
+}
@@ -0,0 +1,31 @@
+#shader vertex
+#version 410 core
+
+layout(location = 0) in vec4 position;
+layout(location = 1) in vec2 texCoord;
+
+uniform mat4 u_MVP;
+
+out vec2 v_TexCoord;
+
+void main()
+{
+	gl_Position = u_MVP * position;
+	v_TexCoord = texCoord;
+}
+
+#shader fragment
+#version 410 core
+
+layout(location = 0) out vec4 color;
+
+in vec2 v_TexCoord;
+
+uniform sampler2D u_Texture;
+
+void main()
+{
+	vec4 texColor = texture(u_Texture, vec2(v_TexCoord.x, 1-v_TexCoord.y));
+	float grey = dot(texColor.rgb, vec3(0.2126, 0.7152, 0.0722));
+	color = vec4(vec3(grey), texColor.a);
+}
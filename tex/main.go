@@ -1,10 +1,13 @@
 package main
 
 import (
+	"flag"
 	"github.com/go-gl/gl/v2.1/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/kevholditch/opengl-playground/render"
+	"log"
+	"math"
+	"os"
 	"runtime"
 )
 
@@ -18,19 +21,26 @@ func init() {
 }
 
 func main() {
-
-	cleanUp := render.Initialise()
-	defer cleanUp()
-
-	w, err := render.NewWindow(render.Config{
+	record := flag.String("record", "", "record input to this file until the window is closed")
+	play := flag.String("play", "", "play back input recorded with -record, save a screenshot of the last frame and exit")
+	preview := flag.Bool("preview", false, "open a second window previewing the texture")
+	cfg := render.Config{
 		MajorVersion: 3,
 		MinorVersion: 2,
 		Width:        width,
 		Height:       height,
 		Title:        "Texture Demo - Kevin Holditch",
 		SwapInterval: 1,
-	})
+		Resizable:    true,
+	}
+	if err := cfg.ParseFlags(flag.CommandLine, os.Args[1:]); err != nil {
+		panic(err)
+	}
+
+	cleanUp := render.Initialise()
+	defer cleanUp()
 
+	w, err := render.NewWindow(cfg)
 	if err != nil {
 		panic(err)
 	}
@@ -52,7 +62,8 @@ func main() {
 	va := render.NewVertexArray()
 	ib := render.NewIndexBuffer(indices)
 
-	proj := mgl32.Ortho(0, width, 0, height, -1.0, 1.0)
+	camera := render.NewOrthoCamera(w)
+	proj := camera.Projection()
 
 	va.AddBuffer(render.NewVertexBuffer(positions), render.NewVertexBufferLayout().AddLayoutFloats(2).AddLayoutFloats(2))
 
@@ -71,7 +82,7 @@ func main() {
 		panic(err)
 	}
 
-	texture, err := render.NewTextureFromFile("./tex/form3.png")
+	texture, err := render.NewTextureFromFile("./tex/form3.png", render.MipmappedTextureOptions())
 	if err != nil {
 		panic(err)
 	}
@@ -85,46 +96,140 @@ func main() {
 
 	x, y := float32(0), float32(0)
 	vx, vy := float32(0), float32(0)
+	zoom := float32(1)
+
+	actions, err := render.LoadActionMap("./tex/actions.json")
+	if err != nil {
+		panic(err)
+	}
+	w.SetActionMap(actions)
+	w.SaveScreenshotsOn(render.KeyF12, "screenshots", render.ScreenshotOptions{PixelBuffers: true})
+	w.OnKeyPress(func(key render.Key) {
+		if key == render.KeyF11 {
+			if err := w.SetFullscreen(!w.IsFullscreen()); err != nil {
+				panic(err)
+			}
+		}
+	})
+
+	if *play != "" {
+		recording, err := render.LoadInputRecording(*play)
+		if err != nil {
+			panic(err)
+		}
+		w.Play(recording)
+	}
+	if *record != "" {
+		w.StartRecording(render.DefaultTimeStep)
+	}
 
 	increment := float32(5)
-	w.OnKeyPress(func(key int) {
 
-		switch key {
-		// move model
-		case 70:
+	loop := render.NewWindowLoop().Add(w, func() {
+
+		// move model one step per key press
+		if w.ActionPressed("model_right") {
 			x += increment
-		case 65:
+		}
+		if w.ActionPressed("model_left") {
 			x -= increment
-		case 83:
+		}
+		if w.ActionPressed("model_down") {
 			y -= increment
-		case 68:
+		}
+		if w.ActionPressed("model_up") {
 			y += increment
-
-			// move camera/view
-		case 90:
-			vx += increment
-		case 88:
-			vx -= increment
-		case 67:
-			vy -= increment
-		case 86:
-			vy += increment
 		}
-	})
 
-	for !w.ShouldClose() {
+		// pan the camera/view by dragging, zoom around the cursor with the wheel
+		if w.IsMouseDown(render.MouseButtonLeft) {
+			dx, dy := w.CursorDelta()
+			vx += float32(dx)
+			vy -= float32(dy)
+		}
+		if _, scroll := w.ScrollDelta(); scroll != 0 {
+			cx, cy := w.CursorPos()
+			_, viewHeight := camera.Size()
+			sx, sy := float32(cx)-x, viewHeight-float32(cy)-y
+			newZoom := zoom * float32(math.Pow(1.1, scroll))
+			vx = sx - (newZoom/zoom)*(sx-vx)
+			vy = sy - (newZoom/zoom)*(sy-vy)
+			zoom = newZoom
+		}
 
 		render.Clear()
 
 		program.Bind()
-		v := mgl32.Ident4().Mul4(mgl32.Translate3D(vx, vy, 0))
+		v := mgl32.Ident4().Mul4(mgl32.Translate3D(vx, vy, 0)).Mul4(mgl32.Scale3D(zoom, zoom, 1))
 		m := mgl32.Ident4().Mul4(mgl32.Translate3D(x, y, 0))
-		mvp := proj.Mul4(m).Mul4(v)
+		mvp := camera.Projection().Mul4(m).Mul4(v)
 		program.SetUniformMat4f("u_MVP", mvp)
 
 		render.Render(va, ib, program)
 
-		w.SwapBuffers()
-		glfw.PollEvents()
+		if *play != "" && !w.Playing() {
+			img, err := w.Screenshot()
+			if err != nil {
+				panic(err)
+			}
+			file, err := render.SavePNG("screenshots", "playback", img)
+			if err != nil {
+				panic(err)
+			}
+			log.Printf("playback finished, saved %s", file)
+			w.Close()
+		}
+	})
+
+	var texPreview *texturePreview
+	if *preview {
+		texPreview, err = newTexturePreview(w, program, ib, texture)
+		if err != nil {
+			panic(err)
+		}
+		loop.Add(texPreview.window, texPreview.draw)
+	}
+
+	// drag images onto the window to swap the texture, or .shader files to swap the program
+	vertexFile, fragmentFile := "./tex/vertex.shader", "./tex/fragment.shader"
+	w.OnFileDrop(func(paths []string) {
+		for _, path := range paths {
+			switch {
+			case render.IsTextureFile(path):
+				dropped, err := render.NewTextureFromFile(path, render.MipmappedTextureOptions())
+				if err != nil {
+					log.Printf("could not load dropped %s: %v", path, err)
+					continue
+				}
+				texture.Delete()
+				texture = dropped
+				texture.Bind(0)
+			case render.IsShaderFile(path):
+				dropped, err := loadDroppedProgram(path, &vertexFile, &fragmentFile)
+				if err != nil {
+					log.Printf("could not load dropped %s: %v", path, err)
+					continue
+				}
+				program.Delete()
+				program = dropped
+				program.Bind()
+				program.SetUniformI1("u_Texture", 0)
+			}
+		}
+		if texPreview != nil {
+			texPreview.texture, texPreview.program = texture, program
+		}
+	})
+
+	loop.Run()
+
+	if *record != "" {
+		recording, err := w.StopRecording()
+		if err != nil {
+			panic(err)
+		}
+		if err := recording.Save(*record); err != nil {
+			panic(err)
+		}
 	}
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/kevholditch/opengl-playground/render"
+)
+
+// texturePreview is a tool window showing the whole texture, drawn with the
+// main window's program, index buffer and texture through a shared context
+type texturePreview struct {
+	window  *render.Window
+	camera  *render.OrthoCamera
+	va      *render.VertexArray
+	ib      *render.IndexBuffer
+	program *render.Program
+	texture *render.Texture
+}
+
+func newTexturePreview(main *render.Window, program *render.Program, ib *render.IndexBuffer, texture *render.Texture) (*texturePreview, error) {
+	w, err := render.NewWindow(render.Config{
+		MajorVersion: 3,
+		MinorVersion: 2,
+		Width:        256,
+		Height:       256,
+		Title:        "Texture Preview",
+		Resizable:    true,
+		Floating:     true,
+		Share:        main,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer main.MakeCurrent()
+
+	// vertex arrays are not shared between contexts so the preview needs its own
+	positions := []float32{
+		0, 0, 0.0, 0.0,
+		1, 0, 1.0, 0.0,
+		1, 1, 1.0, 1.0,
+		0, 1, 0.0, 1.0,
+	}
+	va := render.NewVertexArray()
+	va.AddBuffer(render.NewVertexBuffer(positions), render.NewVertexBufferLayout().AddLayoutFloats(2).AddLayoutFloats(2))
+	va.UnBind()
+	render.UseDefaultBlending()
+
+	return &texturePreview{
+		window:  w,
+		camera:  render.NewOrthoCamera(w),
+		va:      va,
+		ib:      ib,
+		program: program,
+		texture: texture,
+	}, nil
+}
+
+func (p *texturePreview) draw() {
+	render.Clear()
+
+	width, height := p.camera.Size()
+	p.texture.Bind(0)
+	p.program.Bind()
+	p.program.SetUniformMat4f("u_MVP", p.camera.Projection().Mul4(mgl32.Scale3D(width, height, 1)))
+
+	render.Render(p.va, p.ib, p.program)
+}